@@ -8,8 +8,9 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/aliexe/ms-priceFetcher/proto"
 	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	"github.com/aliexe/ms-priceFetcher/proto"
+	protov2 "github.com/aliexe/ms-priceFetcher/proto/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -71,4 +72,15 @@ func NewGRPCClient(addr string) (proto.PriceFetcherClient, error) {
 	}
 
 	return proto.NewPriceFetcherClient(conn), nil
-}
\ No newline at end of file
+}
+
+// NewGRPCClientV2 creates a gRPC client for the v2 API, which returns
+// prices as exact money amounts
+func NewGRPCClientV2(addr string) (protov2.PriceFetcherClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+	}
+
+	return protov2.NewPriceFetcherClient(conn), nil
+}
@@ -6,12 +6,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/cache"
 	"github.com/aliexe/ms-priceFetcher/internal/config"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
 	"github.com/aliexe/ms-priceFetcher/internal/server"
 	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
 	"github.com/joho/godotenv"
 )
 
@@ -27,16 +32,61 @@ func main() {
 		log.Fatalf("Configuration error: %v", err)
 	}
 
-	svc := service.NewLoggingService(service.NewPriceService())
-	alertSvc := service.NewAlertService(service.NewPriceService())
+	historyStore, err := history.NewFileStore(cfg.HistoryDir)
+	if err != nil {
+		log.Fatalf("Failed to open history store: %v", err)
+	}
+
+	priceSvc, err := service.NewFailoverPriceService(cfg.Providers, cfg.ProviderTimeout, service.ProviderOptions{
+		Cache:   newCache(cfg),
+		History: historyStore,
+		AlphaVantage: &service.AlphaVantageOptions{
+			RequestsPerMinute:    cfg.AlphaVantageRequestsPerMinute,
+			RequestsPerDay:       cfg.AlphaVantageRequestsPerDay,
+			StaleWhileRevalidate: cfg.AlphaVantageStaleWhileRevalidate,
+			MaxStale:             cfg.AlphaVantageMaxStale,
+		},
+		BinanceBaseURL: cfg.BinanceBaseURL,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create price providers: %v", err)
+	}
+
+	tickStore, err := ticks.NewStore(cfg.TicksDir, ticks.Options{
+		Retention:          cfg.TickRetention,
+		DownsampleAfter:    cfg.TickDownsampleAfter,
+		DownsampleInterval: cfg.TickDownsampleInterval,
+	})
+	if err != nil {
+		log.Fatalf("Failed to open tick store: %v", err)
+	}
+	defer tickStore.Close()
+
+	// Record every price served to clients, streams and the alert checker
+	recordedSvc := service.NewRecordingService(priceSvc, tickStore)
+	svc := service.NewLoggingService(recordedSvc)
+	alertSvc := service.NewAlertService(recordedSvc)
+	// Streams share one poll per ticker and interval
+	priceHub := pricehub.NewHub(svc, pricehub.Options{
+		BufferSize:  cfg.StreamBufferSize,
+		MinInterval: cfg.StreamMinInterval,
+		MaxInterval: cfg.StreamMaxInterval,
+		Jitter:      float64(cfg.StreamJitterPercent) / 100,
+	})
 
 	log.Printf("Starting Price Fetcher Service...")
+	log.Printf("Price providers: %s", strings.Join(priceSvc.Providers(), ", "))
+	log.Printf("Cache backend: %s", cfg.CacheBackend)
+	log.Printf("History store: %s", cfg.HistoryDir)
+	log.Printf("Tick store: %s", cfg.TicksDir)
 	log.Printf("JSON API: http://localhost%s", cfg.JSONAddr)
 	log.Printf("gRPC API: localhost%s", cfg.GRPCAddr)
+	log.Printf("Admin API: http://%s", cfg.AdminAddr)
 
 	// Create servers
-	httpServer := server.NewJSONAPIServer(cfg.JSONAddr, svc, alertSvc)
-	grpcServer, err := server.MakeGRPCServer(cfg.GRPCAddr, svc)
+	httpServer := server.NewJSONAPIServer(cfg.JSONAddr, svc, alertSvc, tickStore, priceSvc, priceHub)
+	adminServer := server.NewAdminServer(cfg.AdminAddr)
+	grpcServer, err := server.MakeGRPCServer(cfg.GRPCAddr, svc, alertSvc, priceSvc, priceHub)
 	if err != nil {
 		log.Fatalf("Failed to create gRPC server: %v", err)
 	}
@@ -45,6 +95,7 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go alertSvc.StartAlertChecker(ctx, 30*time.Second)
+	go tickStore.StartCompactor(ctx, time.Hour)
 
 	// Channel to listen for shutdown signals
 	shutdownChan := make(chan os.Signal, 1)
@@ -58,6 +109,13 @@ func main() {
 		}
 	}()
 
+	adminErrChan := make(chan error, 1)
+	go func() {
+		if err := adminServer.Run(); err != nil && err != http.ErrServerClosed {
+			adminErrChan <- err
+		}
+	}()
+
 	grpcErrChan := make(chan error, 1)
 	go func() {
 		if err := grpcServer.Run(); err != nil {
@@ -69,6 +127,8 @@ func main() {
 	select {
 	case err := <-httpErrChan:
 		log.Fatalf("HTTP server error: %v", err)
+	case err := <-adminErrChan:
+		log.Fatalf("Admin server error: %v", err)
 	case err := <-grpcErrChan:
 		log.Fatalf("gRPC server error: %v", err)
 	case sig := <-shutdownChan:
@@ -85,6 +145,9 @@ func main() {
 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
 			log.Printf("HTTP server shutdown error: %v", err)
 		}
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Admin server shutdown error: %v", err)
+		}
 
 		// Shutdown gRPC server
 		grpcServer.Stop()
@@ -92,3 +155,19 @@ func main() {
 		log.Println("Servers stopped gracefully")
 	}
 }
+
+// newCache creates the cache backend selected by the configuration
+func newCache(cfg *config.Config) cache.Cache {
+	if cfg.CacheBackend != config.CacheBackendRedis {
+		return cache.NewLRU(cfg.CacheMaxEntries)
+	}
+
+	redis := cache.NewRedis(cfg.RedisAddr, "pricefetcher:", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := redis.Ping(ctx); err != nil {
+		log.Printf("Warning: Redis at %s is not reachable, requests will go upstream until it is: %v", cfg.RedisAddr, err)
+	}
+	return redis
+}
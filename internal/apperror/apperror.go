@@ -0,0 +1,139 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds shared by every transport
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrInvalidArgument     = errors.New("invalid argument")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrInternal            = errors.New("internal error")
+)
+
+// kinds lists the error kinds in the order they are matched. When several
+// are present, e.g. in a joined error from multiple providers, the first
+// one listed decides how the error is reported.
+var kinds = []error{
+	ErrInvalidArgument,
+	ErrRateLimited,
+	ErrUpstreamUnavailable,
+	ErrNotFound,
+	ErrInternal,
+}
+
+// Error attaches a kind to a message and an optional underlying error
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of the given kind
+func New(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap attaches a kind and message to err
+func Wrap(kind error, err error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// NotFound creates an ErrNotFound error
+func NotFound(format string, args ...any) error {
+	return New(ErrNotFound, format, args...)
+}
+
+// InvalidArgument creates an ErrInvalidArgument error
+func InvalidArgument(format string, args ...any) error {
+	return New(ErrInvalidArgument, format, args...)
+}
+
+// KindOf returns the kind of err, or ErrInternal if it has none
+func KindOf(err error) error {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return ErrInternal
+}
+
+// HTTPStatus returns the HTTP status code for err
+func HTTPStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) && KindOf(err) == ErrInternal {
+		return http.StatusGatewayTimeout
+	}
+
+	switch KindOf(err) {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrInvalidArgument:
+		return http.StatusBadRequest
+	case ErrUpstreamUnavailable:
+		return http.StatusBadGateway
+	case ErrRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCCode returns the gRPC status code for err
+func GRPCCode(err error) codes.Code {
+	if KindOf(err) == ErrInternal {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			return codes.Canceled
+		}
+	}
+
+	switch KindOf(err) {
+	case ErrNotFound:
+		return codes.NotFound
+	case ErrInvalidArgument:
+		return codes.InvalidArgument
+	case ErrUpstreamUnavailable:
+		return codes.Unavailable
+	case ErrRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// GRPCStatus converts err into a gRPC status error
+func GRPCStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(GRPCCode(err), err.Error())
+}
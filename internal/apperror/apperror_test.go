@@ -0,0 +1,107 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   error
+		wantStatus int
+		wantCode   codes.Code
+	}{
+		{
+			name:       "Not found",
+			err:        NotFound("ticker not found: %s", "XYZ"),
+			wantKind:   ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   codes.NotFound,
+		},
+		{
+			name:       "Invalid argument",
+			err:        InvalidArgument("ticker is required"),
+			wantKind:   ErrInvalidArgument,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "Upstream unavailable",
+			err:        Wrap(ErrUpstreamUnavailable, errors.New("connection refused"), "failed to call GLOBAL_QUOTE"),
+			wantKind:   ErrUpstreamUnavailable,
+			wantStatus: http.StatusBadGateway,
+			wantCode:   codes.Unavailable,
+		},
+		{
+			name:       "Rate limited",
+			err:        New(ErrRateLimited, "slow down"),
+			wantKind:   ErrRateLimited,
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   codes.ResourceExhausted,
+		},
+		{
+			name:       "Wrapped with fmt",
+			err:        fmt.Errorf("provider: %w", NotFound("no data")),
+			wantKind:   ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   codes.NotFound,
+		},
+		{
+			name:       "Joined prefers rate limit over not found",
+			err:        errors.Join(NotFound("mock: no data"), New(ErrRateLimited, "alphavantage: throttled")),
+			wantKind:   ErrRateLimited,
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   codes.ResourceExhausted,
+		},
+		{
+			name:       "Plain error",
+			err:        errors.New("boom"),
+			wantKind:   ErrInternal,
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   codes.Internal,
+		},
+		{
+			name:       "Deadline exceeded",
+			err:        fmt.Errorf("fetch: %w", context.DeadlineExceeded),
+			wantKind:   ErrInternal,
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.wantKind {
+				t.Errorf("KindOf() = %v, want %v", got, tt.wantKind)
+			}
+			if got := HTTPStatus(tt.err); got != tt.wantStatus {
+				t.Errorf("HTTPStatus() = %v, want %v", got, tt.wantStatus)
+			}
+			if got := GRPCCode(tt.err); got != tt.wantCode {
+				t.Errorf("GRPCCode() = %v, want %v", got, tt.wantCode)
+			}
+			if got := status.Code(GRPCStatus(tt.err)); got != tt.wantCode {
+				t.Errorf("GRPCStatus() code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	err := Wrap(ErrUpstreamUnavailable, errors.New("connection refused"), "failed to call %s", "GLOBAL_QUOTE")
+	if got := err.Error(); got != "failed to call GLOBAL_QUOTE: connection refused" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if got := NotFound("alert not found: %s", "abc").Error(); got != "alert not found: abc" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cache stores byte values under string keys with a per-entry TTL.
+// A non-positive TTL keeps the entry until it is evicted or deleted.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+	Delete(ctx context.Context, key string) error
+	Clear(ctx context.Context) error
+}
+
+// Typed is a view of a Cache that stores values of type T as JSON under a
+// key prefix, so several typed views can share one backend
+type Typed[T any] struct {
+	cache  Cache
+	prefix string
+}
+
+// NewTyped creates a typed view of c whose keys are prefixed with prefix
+func NewTyped[T any](c Cache, prefix string) *Typed[T] {
+	return &Typed[T]{cache: c, prefix: prefix}
+}
+
+// Get returns the value stored under key
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var value T
+
+	data, found, err := t.cache.Get(ctx, t.prefix+key)
+	if err != nil || !found {
+		return value, false, err
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false, fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for cache: %w", key, err)
+	}
+	return t.cache.Set(ctx, t.prefix+key, data, ttl)
+}
+
+// TTL returns how long the value under key has left. A found key with a
+// zero duration never expires.
+func (t *Typed[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return t.cache.TTL(ctx, t.prefix+key)
+}
+
+// Delete removes the value under key
+func (t *Typed[T]) Delete(ctx context.Context, key string) error {
+	return t.cache.Delete(ctx, t.prefix+key)
+}
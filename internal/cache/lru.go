@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-memory Cache that evicts the least recently used entry once
+// it holds capacity entries. All operations are O(1).
+type LRU struct {
+	mutex    sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // front is most recently used
+}
+
+type lruItem struct {
+	key    string
+	value  []byte
+	expiry time.Time
+}
+
+// NewLRU creates an in-memory cache holding at most capacity entries
+func NewLRU(capacity int) *LRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value under key and marks it as recently used
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	el, ok := c.live(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entry if
+// the cache is full
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*lruItem)
+		item.value = value
+		item.expiry = expiry
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, value: value, expiry: expiry})
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// TTL returns how long the entry under key has left
+func (c *LRU) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	el, ok := c.live(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	expiry := el.Value.(*lruItem).expiry
+	if expiry.IsZero() {
+		return 0, true, nil
+	}
+	return time.Until(expiry), true, nil
+}
+
+// Delete removes the entry under key
+func (c *LRU) Delete(ctx context.Context, key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// Clear removes every entry
+func (c *LRU) Clear(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *LRU) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// live returns the element for key, dropping it if it has expired
+func (c *LRU) live(key string) (*list.Element, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	expiry := el.Value.(*lruItem).expiry
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		c.remove(el)
+		return nil, false
+	}
+	return el, true
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruItem).key)
+}
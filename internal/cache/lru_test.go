@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+
+	// Touch "a" so "b" becomes the oldest
+	if _, found, _ := c.Get(ctx, "a"); !found {
+		t.Fatal("Expected a to be cached")
+	}
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, found, _ := c.Get(ctx, "b"); found {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found, _ := c.Get(ctx, key); !found {
+			t.Errorf("Expected %s to be cached", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	c.Set(ctx, "short", []byte("1"), 20*time.Millisecond)
+	c.Set(ctx, "forever", []byte("2"), 0)
+
+	ttl, found, _ := c.TTL(ctx, "short")
+	if !found || ttl <= 0 || ttl > 20*time.Millisecond {
+		t.Errorf("TTL(short) = %v, %v; want (0, 20ms], true", ttl, found)
+	}
+	if ttl, found, _ := c.TTL(ctx, "forever"); !found || ttl != 0 {
+		t.Errorf("TTL(forever) = %v, %v; want 0, true", ttl, found)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, found, _ := c.Get(ctx, "short"); found {
+		t.Error("Expected short to have expired")
+	}
+	if _, found, _ := c.Get(ctx, "forever"); !found {
+		t.Error("Expected forever to still be cached")
+	}
+}
+
+func TestLRU_DeleteAndClear(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+
+	c.Delete(ctx, "a")
+	if _, found, _ := c.Get(ctx, "a"); found {
+		t.Error("Expected a to be deleted")
+	}
+
+	c.Clear(ctx)
+	if c.Len() != 0 {
+		t.Errorf("Len() after Clear = %d, want 0", c.Len())
+	}
+}
+
+func TestTyped_RoundTrip(t *testing.T) {
+	type quote struct {
+		Price float64
+		AsOf  time.Time
+	}
+
+	ctx := context.Background()
+	backend := NewLRU(10)
+	prices := NewTyped[quote](backend, "price:")
+	names := NewTyped[string](backend, "name:")
+
+	want := quote{Price: 150.25, AsOf: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	if err := prices.Set(ctx, "AAPL", want, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	names.Set(ctx, "AAPL", "Apple Inc.", 0)
+
+	got, found, err := prices.Get(ctx, "AAPL")
+	if err != nil || !found {
+		t.Fatalf("Get() = %v, %v, %v", got, found, err)
+	}
+	if got.Price != want.Price || !got.AsOf.Equal(want.AsOf) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if name, _, _ := names.Get(ctx, "AAPL"); name != "Apple Inc." {
+		t.Errorf("names.Get() = %q, want %q", name, "Apple Inc.")
+	}
+
+	if _, found, _ := backend.Get(ctx, "price:AAPL"); !found {
+		t.Error("Expected typed view to store under its prefix")
+	}
+}
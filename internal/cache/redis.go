@@ -0,0 +1,352 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRedisPoolSize    = 10
+	defaultRedisDialTimeout = 2 * time.Second
+
+	// redisScanCount is the number of keys Clear asks SCAN to look at per
+	// call, and so roughly the size of each batch it deletes
+	redisScanCount = 100
+)
+
+// Redis is a Cache backed by any server speaking the Redis protocol (RESP).
+// Keys are namespaced with a prefix so Clear only touches this service's
+// entries.
+type Redis struct {
+	addr        string
+	prefix      string
+	dialTimeout time.Duration
+	idle        chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// redisError is an error reply sent by the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedis creates a cache talking to the server at addr. Connections are
+// opened on demand and up to poolSize idle ones are kept for reuse.
+func NewRedis(addr, prefix string, poolSize int) *Redis {
+	if poolSize < 1 {
+		poolSize = defaultRedisPoolSize
+	}
+	return &Redis{
+		addr:        addr,
+		prefix:      prefix,
+		dialTimeout: defaultRedisDialTimeout,
+		idle:        make(chan *redisConn, poolSize),
+	}
+}
+
+// Ping checks that the server is reachable
+func (c *Redis) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Get returns the value under key
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", c.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", c.prefix + key, string(value)}
+	if ttl > 0 {
+		ms := ttl.Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// TTL returns how long the entry under key has left
+func (c *Redis) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	reply, err := c.do(ctx, "PTTL", c.prefix+key)
+	if err != nil {
+		return 0, false, err
+	}
+
+	ms, ok := reply.(int64)
+	if !ok {
+		return 0, false, fmt.Errorf("redis: unexpected PTTL reply %T", reply)
+	}
+
+	switch {
+	case ms == -2:
+		return 0, false, nil
+	case ms == -1:
+		return 0, true, nil
+	default:
+		return time.Duration(ms) * time.Millisecond, true, nil
+	}
+}
+
+// Delete removes the entry under key
+func (c *Redis) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", c.prefix+key)
+	return err
+}
+
+// Clear removes every entry under the cache's prefix. Keys are found with
+// SCAN and deleted in batches, so a large shared server is never blocked
+// for long.
+func (c *Redis) Clear(ctx context.Context) error {
+	pattern := escapeGlob(c.prefix) + "*"
+	cursor := "0"
+	for {
+		reply, err := c.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			return err
+		}
+
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply %T", reply)
+		}
+		next, ok := page[0].([]byte)
+		if !ok {
+			return fmt.Errorf("redis: unexpected SCAN cursor %T", page[0])
+		}
+		keys, ok := page[1].([]any)
+		if !ok {
+			return fmt.Errorf("redis: unexpected SCAN keys %T", page[1])
+		}
+
+		if len(keys) > 0 {
+			args := []string{"DEL"}
+			for _, key := range keys {
+				if b, ok := key.([]byte); ok {
+					args = append(args, string(b))
+				}
+			}
+			if _, err := c.do(ctx, args...); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return nil
+		}
+	}
+}
+
+// escapeGlob escapes the characters that are special in Redis match
+// patterns
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// do sends one command and reads its reply
+func (c *Redis) do(ctx context.Context, args ...string) (any, error) {
+	rc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		rc.conn.SetDeadline(deadline)
+	} else {
+		rc.conn.SetDeadline(time.Time{})
+	}
+	// Cancelling ctx unblocks the command in flight by expiring the
+	// connection's deadline
+	stop := context.AfterFunc(ctx, func() { rc.conn.SetDeadline(time.Now()) })
+
+	if err := writeCommand(rc.conn, args); err != nil {
+		stop()
+		rc.conn.Close()
+		return nil, contextError(ctx, fmt.Errorf("redis: failed to send %s: %w", args[0], err))
+	}
+
+	reply, err := readReply(rc.reader)
+	if !stop() {
+		// The deadline was expired underneath the command, so the
+		// connection cannot be reused
+		rc.conn.Close()
+		if err == nil {
+			return reply, nil
+		}
+		return nil, contextError(ctx, fmt.Errorf("redis: failed to read %s reply: %w", args[0], err))
+	}
+	if err != nil {
+		var replyErr redisError
+		if errors.As(err, &replyErr) {
+			// The connection is still in a clean state
+			c.put(rc)
+			return nil, err
+		}
+		rc.conn.Close()
+		return nil, contextError(ctx, fmt.Errorf("redis: failed to read %s reply: %w", args[0], err))
+	}
+
+	c.put(rc)
+	return reply, nil
+}
+
+// contextError reports a failed command as cancelled when ctx was
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
+
+func (c *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case rc := <-c.idle:
+		return rc, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect to %s: %w", c.addr, err)
+	}
+	return &redisConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func (c *Redis) put(rc *redisConn) {
+	select {
+	case c.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+// Close closes all idle connections
+func (c *Redis) Close() error {
+	for {
+		select {
+		case rc := <-c.idle:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// writeCommand encodes args as a RESP array of bulk strings
+func writeCommand(w io.Writer, args []string) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readReply decodes one RESP reply. Simple strings are returned as string,
+// integers as int64, bulk strings as []byte, arrays as []any and nil
+// replies as nil. Error replies are returned as redisError.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", line[0])
+	}
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("malformed reply line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,365 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal in-process server speaking enough RESP for the
+// Redis cache backend
+type fakeRedis struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	values   map[string]string
+	expiry   map[string]time.Time
+	scans    int
+	scanKeys []string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	s := &fakeRedis{
+		listener: listener,
+		values:   make(map[string]string),
+		expiry:   make(map[string]time.Time),
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeRedis) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+		if _, err := conn.Write([]byte(s.exec(args))); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) exec(args []string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := s.live(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "SET":
+		s.values[args[1]] = args[2]
+		delete(s.expiry, args[1])
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			ms, err := strconv.Atoi(args[4])
+			if err != nil {
+				return "-ERR value is not an integer\r\n"
+			}
+			s.expiry[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		return "+OK\r\n"
+	case "PTTL":
+		if _, ok := s.live(args[1]); !ok {
+			return ":-2\r\n"
+		}
+		expiry, ok := s.expiry[args[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", time.Until(expiry).Milliseconds())
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.values[key]; ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.expiry, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "SCAN":
+		// A scan walks the keys present when it started, in sorted order;
+		// the cursor is the index of the next one. MATCH is assumed to be
+		// a prefix pattern.
+		cursor, _ := strconv.Atoi(args[1])
+		prefix := strings.TrimSuffix(args[3], "*")
+		count, _ := strconv.Atoi(args[5])
+		if cursor == 0 {
+			s.scanKeys = s.scanKeys[:0]
+			for key := range s.values {
+				s.scanKeys = append(s.scanKeys, key)
+			}
+			sort.Strings(s.scanKeys)
+		}
+		keys := s.scanKeys
+
+		end := min(cursor+count, len(keys))
+		var matched []string
+		for _, key := range keys[cursor:end] {
+			if strings.HasPrefix(key, prefix) {
+				matched = append(matched, key)
+			}
+		}
+		next := end
+		if end == len(keys) {
+			next = 0
+		}
+		s.scans++
+		reply := "*2\r\n" + bulk(strconv.Itoa(next)) + fmt.Sprintf("*%d\r\n", len(matched))
+		for _, key := range matched {
+			reply += bulk(key)
+		}
+		return reply
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func (s *fakeRedis) live(key string) (string, bool) {
+	value, ok := s.values[key]
+	if !ok {
+		return "", false
+	}
+	if expiry, ok := s.expiry[key]; ok && time.Now().After(expiry) {
+		delete(s.values, key)
+		delete(s.expiry, key)
+		return "", false
+	}
+	return value, true
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func TestRedis_GetSetDelete(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedis(server.addr(), "test:", 2)
+	defer c.Close()
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if _, found, err := c.Get(ctx, "missing"); err != nil || found {
+		t.Errorf("Get(missing) = %v, %v; want not found", found, err)
+	}
+
+	value := []byte("line1\r\nline2")
+	if err := c.Set(ctx, "AAPL", value, 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, found, err := c.Get(ctx, "AAPL")
+	if err != nil || !found || string(got) != string(value) {
+		t.Errorf("Get(AAPL) = %q, %v, %v; want %q", got, found, err, value)
+	}
+
+	server.mutex.Lock()
+	_, stored := server.values["test:AAPL"]
+	server.mutex.Unlock()
+	if !stored {
+		t.Error("Expected key to be stored under the prefix")
+	}
+
+	if err := c.Delete(ctx, "AAPL"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, found, _ := c.Get(ctx, "AAPL"); found {
+		t.Error("Expected AAPL to be deleted")
+	}
+}
+
+func TestRedis_TTL(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedis(server.addr(), "test:", 2)
+	defer c.Close()
+	ctx := context.Background()
+
+	c.Set(ctx, "short", []byte("1"), 50*time.Millisecond)
+	c.Set(ctx, "forever", []byte("2"), 0)
+
+	ttl, found, err := c.TTL(ctx, "short")
+	if err != nil || !found || ttl <= 0 || ttl > 50*time.Millisecond {
+		t.Errorf("TTL(short) = %v, %v, %v; want (0, 50ms]", ttl, found, err)
+	}
+	if ttl, found, _ := c.TTL(ctx, "forever"); !found || ttl != 0 {
+		t.Errorf("TTL(forever) = %v, %v; want 0, true", ttl, found)
+	}
+	if _, found, _ := c.TTL(ctx, "missing"); found {
+		t.Error("TTL(missing) reported found")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, found, _ := c.Get(ctx, "short"); found {
+		t.Error("Expected short to have expired")
+	}
+}
+
+func TestRedis_ClearOnlyOwnPrefix(t *testing.T) {
+	server := newFakeRedis(t)
+	ours := NewRedis(server.addr(), "ours:", 2)
+	theirs := NewRedis(server.addr(), "theirs:", 2)
+	defer ours.Close()
+	defer theirs.Close()
+	ctx := context.Background()
+
+	// Enough keys that Clear takes several SCAN batches
+	for i := 0; i < 2*redisScanCount+50; i++ {
+		ours.Set(ctx, strconv.Itoa(i), []byte("1"), 0)
+	}
+	theirs.Set(ctx, "a", []byte("3"), 0)
+
+	if err := ours.Clear(ctx); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	server.mutex.Lock()
+	scans, remaining := server.scans, len(server.values)
+	server.mutex.Unlock()
+	if scans < 3 {
+		t.Errorf("Clear() made %d SCAN calls, want the keyspace scanned in batches", scans)
+	}
+	if remaining != 1 {
+		t.Errorf("%d keys left after Clear(), want only the other prefix's", remaining)
+	}
+	if _, found, _ := theirs.Get(ctx, "a"); !found {
+		t.Error("Expected other prefixes to be kept")
+	}
+}
+
+func TestRedis_TypedConcurrentUse(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedis(server.addr(), "", 4)
+	defer c.Close()
+	prices := NewTyped[float64](c, "price:")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("T%d", i)
+			if err := prices.Set(ctx, key, float64(i), time.Minute); err != nil {
+				t.Errorf("Set(%s) error = %v", key, err)
+				return
+			}
+			price, found, err := prices.Get(ctx, key)
+			if err != nil || !found || price != float64(i) {
+				t.Errorf("Get(%s) = %v, %v, %v", key, price, found, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRedis_Errors(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedis(server.addr(), "", 1)
+	defer c.Close()
+	ctx := context.Background()
+
+	if _, err := c.do(ctx, "FLUSHALL"); err == nil {
+		t.Error("Expected error reply for unknown command")
+	}
+	// An error reply leaves the connection usable
+	if err := c.Ping(ctx); err != nil {
+		t.Errorf("Ping() after error reply = %v", err)
+	}
+
+	unreachable := NewRedis("127.0.0.1:1", "", 1)
+	if err := unreachable.Ping(ctx); err == nil {
+		t.Error("Expected error connecting to closed port")
+	}
+}
+
+func TestRedis_Cancellation(t *testing.T) {
+	// A server that accepts connections but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	c := NewRedis(listener.Addr().String(), "", 1)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	go func() { done <- c.Ping(ctx) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Ping() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Ping() did not return when its context was cancelled")
+	}
+}
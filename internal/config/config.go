@@ -2,24 +2,84 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
-	UseRealData      bool
-	AlphaVantageKey  string
-	JSONAddr         string
-	GRPCAddr         string
+	UseRealData            bool
+	AlphaVantageKey        string
+	JSONAddr               string
+	GRPCAddr               string
+	AdminAddr              string
+	Providers              []string
+	ProviderTimeout        time.Duration
+	CacheBackend           string
+	RedisAddr              string
+	CacheMaxEntries        int
+	HistoryDir             string
+	TicksDir               string
+	TickRetention          time.Duration
+	TickDownsampleAfter    time.Duration
+	TickDownsampleInterval time.Duration
+	StreamBufferSize       int
+	StreamMinInterval      time.Duration
+	StreamMaxInterval      time.Duration
+	StreamJitterPercent    int
+
+	// Alpha Vantage request budgets (0 disables a limit) and staleness
+	// windows
+	AlphaVantageRequestsPerMinute    int
+	AlphaVantageRequestsPerDay       int
+	AlphaVantageStaleWhileRevalidate time.Duration
+	AlphaVantageMaxStale             time.Duration
+
+	// Binance-compatible REST API the binance provider calls; empty uses
+	// Binance itself
+	BinanceBaseURL string
 }
 
+// Supported cache backends
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+)
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
+	useRealData := os.Getenv("USE_REAL_DATA") == "true"
+
 	return &Config{
-		UseRealData:      os.Getenv("USE_REAL_DATA") == "true",
-		AlphaVantageKey:  getEnvWithDefault("ALPHA_VANTAGE_API_KEY", "demo"),
-		JSONAddr:         getEnvWithDefault("JSON_ADDR", ":8080"),
-		GRPCAddr:         getEnvWithDefault("GRPC_ADDR", ":8081"),
+		UseRealData:            useRealData,
+		AlphaVantageKey:        getEnvWithDefault("ALPHA_VANTAGE_API_KEY", "demo"),
+		JSONAddr:               getEnvWithDefault("JSON_ADDR", ":8080"),
+		GRPCAddr:               getEnvWithDefault("GRPC_ADDR", ":8081"),
+		AdminAddr:              getEnvWithDefault("ADMIN_ADDR", "localhost:8082"),
+		Providers:              getProviders(useRealData),
+		ProviderTimeout:        getDurationWithDefault("PROVIDER_TIMEOUT", 10*time.Second),
+		CacheBackend:           getEnvWithDefault("CACHE_BACKEND", CacheBackendMemory),
+		RedisAddr:              getEnvWithDefault("REDIS_ADDR", "localhost:6379"),
+		CacheMaxEntries:        getIntWithDefault("CACHE_MAX_ENTRIES", 1000),
+		HistoryDir:             getEnvWithDefault("HISTORY_DIR", "data/history"),
+		TicksDir:               getEnvWithDefault("TICKS_DIR", "data/ticks"),
+		TickRetention:          getDurationWithDefault("TICK_RETENTION", 30*24*time.Hour),
+		TickDownsampleAfter:    getDurationWithDefault("TICK_DOWNSAMPLE_AFTER", 24*time.Hour),
+		TickDownsampleInterval: getDurationWithDefault("TICK_DOWNSAMPLE_INTERVAL", time.Minute),
+		StreamBufferSize:       getIntWithDefault("STREAM_BUFFER_SIZE", 64),
+		StreamMinInterval:      getDurationWithDefault("STREAM_MIN_INTERVAL", time.Second),
+		StreamMaxInterval:      getDurationWithDefault("STREAM_MAX_INTERVAL", time.Hour),
+		StreamJitterPercent:    getIntWithDefault("STREAM_JITTER_PERCENT", 10),
+
+		AlphaVantageRequestsPerMinute:    getIntWithDefault("ALPHA_VANTAGE_REQUESTS_PER_MINUTE", 5),
+		AlphaVantageRequestsPerDay:       getIntWithDefault("ALPHA_VANTAGE_REQUESTS_PER_DAY", 25),
+		AlphaVantageStaleWhileRevalidate: getDurationWithDefault("ALPHA_VANTAGE_STALE_WHILE_REVALIDATE", time.Minute),
+		AlphaVantageMaxStale:             getDurationWithDefault("ALPHA_VANTAGE_MAX_STALE", time.Hour),
+
+		BinanceBaseURL: getEnvWithDefault("BINANCE_BASE_URL", "https://api.binance.com"),
 	}
 }
 
@@ -28,6 +88,47 @@ func (c *Config) Validate() error {
 	if c.UseRealData && c.AlphaVantageKey == "" {
 		return fmt.Errorf("ALPHA_VANTAGE_API_KEY is required when USE_REAL_DATA=true")
 	}
+	if c.ProviderTimeout < 0 {
+		return fmt.Errorf("PROVIDER_TIMEOUT must not be negative")
+	}
+	if c.TickRetention < 0 || c.TickDownsampleAfter < 0 || c.TickDownsampleInterval < 0 {
+		return fmt.Errorf("TICK_RETENTION, TICK_DOWNSAMPLE_AFTER and TICK_DOWNSAMPLE_INTERVAL must not be negative")
+	}
+	if c.AlphaVantageRequestsPerMinute < 0 || c.AlphaVantageRequestsPerDay < 0 {
+		return fmt.Errorf("ALPHA_VANTAGE_REQUESTS_PER_MINUTE and ALPHA_VANTAGE_REQUESTS_PER_DAY must not be negative")
+	}
+	if c.AlphaVantageStaleWhileRevalidate < 0 || c.AlphaVantageMaxStale < 0 {
+		return fmt.Errorf("ALPHA_VANTAGE_STALE_WHILE_REVALIDATE and ALPHA_VANTAGE_MAX_STALE must not be negative")
+	}
+	if c.BinanceBaseURL != "" {
+		if u, err := url.Parse(c.BinanceBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("BINANCE_BASE_URL must be an absolute URL")
+		}
+	}
+	if c.CacheMaxEntries < 0 {
+		return fmt.Errorf("CACHE_MAX_ENTRIES must not be negative")
+	}
+	if c.StreamBufferSize < 0 {
+		return fmt.Errorf("STREAM_BUFFER_SIZE must not be negative")
+	}
+	if c.StreamMinInterval < 0 || c.StreamMaxInterval < 0 {
+		return fmt.Errorf("STREAM_MIN_INTERVAL and STREAM_MAX_INTERVAL must not be negative")
+	}
+	if c.StreamMinInterval > 0 && c.StreamMaxInterval > 0 && c.StreamMinInterval > c.StreamMaxInterval {
+		return fmt.Errorf("STREAM_MIN_INTERVAL must not exceed STREAM_MAX_INTERVAL")
+	}
+	if c.StreamJitterPercent < 0 || c.StreamJitterPercent > 50 {
+		return fmt.Errorf("STREAM_JITTER_PERCENT must be between 0 and 50")
+	}
+	switch c.CacheBackend {
+	case "", CacheBackendMemory:
+	case CacheBackendRedis:
+		if c.RedisAddr == "" {
+			return fmt.Errorf("REDIS_ADDR is required when CACHE_BACKEND=redis")
+		}
+	default:
+		return fmt.Errorf("unknown CACHE_BACKEND: %s", c.CacheBackend)
+	}
 	return nil
 }
 
@@ -36,4 +137,48 @@ func getEnvWithDefault(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getProviders reads the provider priority list from PRICE_PROVIDERS,
+// falling back to the single provider selected by USE_REAL_DATA
+func getProviders(useRealData bool) []string {
+	var providers []string
+	for _, name := range strings.Split(os.Getenv("PRICE_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			providers = append(providers, name)
+		}
+	}
+	if len(providers) > 0 {
+		return providers
+	}
+
+	if useRealData {
+		return []string{"alphavantage"}
+	}
+	return []string{"mock"}
+}
+
+func getDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue
+	}
+	return duration
+}
+
+func getIntWithDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return n
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -131,6 +132,28 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid redis cache config",
+			config: &Config{
+				CacheBackend: CacheBackendRedis,
+				RedisAddr:    "localhost:6379",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid redis cache config - missing address",
+			config: &Config{
+				CacheBackend: CacheBackendRedis,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid cache backend",
+			config: &Config{
+				CacheBackend: "memcached",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -181,4 +204,172 @@ func TestGetEnvWithDefault(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestLoadConfig_Providers(t *testing.T) {
+	tests := []struct {
+		name          string
+		useRealData   string
+		providers     string
+		wantProviders []string
+	}{
+		{
+			name:          "Mock by default",
+			useRealData:   "false",
+			providers:     "",
+			wantProviders: []string{"mock"},
+		},
+		{
+			name:          "Alpha Vantage with real data",
+			useRealData:   "true",
+			providers:     "",
+			wantProviders: []string{"alphavantage"},
+		},
+		{
+			name:          "Explicit priority list",
+			useRealData:   "true",
+			providers:     "alphavantage, mock",
+			wantProviders: []string{"alphavantage", "mock"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("USE_REAL_DATA", tt.useRealData)
+			t.Setenv("PRICE_PROVIDERS", tt.providers)
+
+			cfg := LoadConfig()
+
+			if len(cfg.Providers) != len(tt.wantProviders) {
+				t.Fatalf("LoadConfig().Providers = %v, want %v", cfg.Providers, tt.wantProviders)
+			}
+			for i, name := range tt.wantProviders {
+				if cfg.Providers[i] != name {
+					t.Errorf("LoadConfig().Providers = %v, want %v", cfg.Providers, tt.wantProviders)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Cache(t *testing.T) {
+	t.Setenv("CACHE_BACKEND", "")
+	t.Setenv("REDIS_ADDR", "")
+	t.Setenv("CACHE_MAX_ENTRIES", "")
+
+	cfg := LoadConfig()
+	if cfg.CacheBackend != CacheBackendMemory || cfg.RedisAddr != "localhost:6379" || cfg.CacheMaxEntries != 1000 {
+		t.Errorf("LoadConfig() cache defaults = %q, %q, %d", cfg.CacheBackend, cfg.RedisAddr, cfg.CacheMaxEntries)
+	}
+
+	t.Setenv("CACHE_BACKEND", "redis")
+	t.Setenv("REDIS_ADDR", "cache:6380")
+	t.Setenv("CACHE_MAX_ENTRIES", "50")
+
+	cfg = LoadConfig()
+	if cfg.CacheBackend != CacheBackendRedis || cfg.RedisAddr != "cache:6380" || cfg.CacheMaxEntries != 50 {
+		t.Errorf("LoadConfig() cache settings = %q, %q, %d", cfg.CacheBackend, cfg.RedisAddr, cfg.CacheMaxEntries)
+	}
+}
+
+func TestLoadConfig_HistoryDir(t *testing.T) {
+	t.Setenv("HISTORY_DIR", "")
+	if cfg := LoadConfig(); cfg.HistoryDir != "data/history" {
+		t.Errorf("LoadConfig().HistoryDir = %q, want %q", cfg.HistoryDir, "data/history")
+	}
+
+	t.Setenv("HISTORY_DIR", "/var/lib/pricefetcher")
+	if cfg := LoadConfig(); cfg.HistoryDir != "/var/lib/pricefetcher" {
+		t.Errorf("LoadConfig().HistoryDir = %q, want %q", cfg.HistoryDir, "/var/lib/pricefetcher")
+	}
+}
+
+func TestLoadConfig_Ticks(t *testing.T) {
+	t.Setenv("TICKS_DIR", "")
+	t.Setenv("TICK_RETENTION", "")
+	t.Setenv("TICK_DOWNSAMPLE_AFTER", "")
+	t.Setenv("TICK_DOWNSAMPLE_INTERVAL", "")
+
+	cfg := LoadConfig()
+	if cfg.TicksDir != "data/ticks" || cfg.TickRetention != 30*24*time.Hour ||
+		cfg.TickDownsampleAfter != 24*time.Hour || cfg.TickDownsampleInterval != time.Minute {
+		t.Errorf("LoadConfig() tick defaults = %q, %v, %v, %v",
+			cfg.TicksDir, cfg.TickRetention, cfg.TickDownsampleAfter, cfg.TickDownsampleInterval)
+	}
+
+	t.Setenv("TICK_RETENTION", "-1h")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected negative TICK_RETENTION to be rejected")
+	}
+}
+
+func TestLoadConfig_StreamBufferSize(t *testing.T) {
+	t.Setenv("STREAM_BUFFER_SIZE", "")
+	if cfg := LoadConfig(); cfg.StreamBufferSize != 64 {
+		t.Errorf("LoadConfig().StreamBufferSize = %d, want 64", cfg.StreamBufferSize)
+	}
+
+	t.Setenv("STREAM_BUFFER_SIZE", "-1")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected negative STREAM_BUFFER_SIZE to be rejected")
+	}
+}
+
+func TestLoadConfig_StreamIntervals(t *testing.T) {
+	t.Setenv("STREAM_MIN_INTERVAL", "")
+	t.Setenv("STREAM_MAX_INTERVAL", "")
+	t.Setenv("STREAM_JITTER_PERCENT", "")
+	cfg := LoadConfig()
+	if cfg.StreamMinInterval != time.Second || cfg.StreamMaxInterval != time.Hour || cfg.StreamJitterPercent != 10 {
+		t.Errorf("LoadConfig() stream intervals = %v, %v, %d%%, want 1s, 1h, 10%%", cfg.StreamMinInterval, cfg.StreamMaxInterval, cfg.StreamJitterPercent)
+	}
+
+	t.Setenv("STREAM_MIN_INTERVAL", "2m")
+	t.Setenv("STREAM_MAX_INTERVAL", "1m")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected STREAM_MIN_INTERVAL above STREAM_MAX_INTERVAL to be rejected")
+	}
+
+	t.Setenv("STREAM_MIN_INTERVAL", "")
+	t.Setenv("STREAM_MAX_INTERVAL", "")
+	t.Setenv("STREAM_JITTER_PERCENT", "60")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected STREAM_JITTER_PERCENT above 50 to be rejected")
+	}
+}
+
+func TestLoadConfig_BinanceBaseURL(t *testing.T) {
+	t.Setenv("BINANCE_BASE_URL", "")
+	if cfg := LoadConfig(); cfg.BinanceBaseURL != "https://api.binance.com" {
+		t.Errorf("LoadConfig() BinanceBaseURL = %q, want the Binance API", cfg.BinanceBaseURL)
+	}
+
+	t.Setenv("BINANCE_BASE_URL", "http://localhost:9000")
+	cfg := LoadConfig()
+	if cfg.BinanceBaseURL != "http://localhost:9000" {
+		t.Errorf("LoadConfig() BinanceBaseURL = %q", cfg.BinanceBaseURL)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	t.Setenv("BINANCE_BASE_URL", "api.binance.com")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected a BINANCE_BASE_URL without a scheme to be rejected")
+	}
+}
+
+func TestLoadConfig_AlphaVantageLimits(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_REQUESTS_PER_MINUTE", "")
+	t.Setenv("ALPHA_VANTAGE_REQUESTS_PER_DAY", "500")
+	t.Setenv("ALPHA_VANTAGE_MAX_STALE", "2h")
+	cfg := LoadConfig()
+	if cfg.AlphaVantageRequestsPerMinute != 5 || cfg.AlphaVantageRequestsPerDay != 500 || cfg.AlphaVantageMaxStale != 2*time.Hour {
+		t.Errorf("LoadConfig() Alpha Vantage limits = %d/min, %d/day, max stale %v", cfg.AlphaVantageRequestsPerMinute, cfg.AlphaVantageRequestsPerDay, cfg.AlphaVantageMaxStale)
+	}
+
+	t.Setenv("ALPHA_VANTAGE_REQUESTS_PER_DAY", "-1")
+	if err := LoadConfig().Validate(); err == nil {
+		t.Error("Expected negative ALPHA_VANTAGE_REQUESTS_PER_DAY to be rejected")
+	}
+}
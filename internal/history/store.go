@@ -0,0 +1,192 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// DateLayout is the layout of the Date field of daily bars
+const DateLayout = "2006-01-02"
+
+// Series is the daily bar history held for one ticker
+type Series struct {
+	Ticker string `json:"ticker"`
+	// Points are sorted by date, oldest first
+	Points []types.HistoricalPricePoint `json:"points"`
+	// FetchedAt is when the series was last brought up to date upstream
+	FetchedAt time.Time `json:"fetched_at"`
+	// Adjusted reports whether the points carry adjusted closes and
+	// corporate actions. Series stored before these were tracked lack them.
+	Adjusted bool `json:"adjusted,omitempty"`
+}
+
+// Store persists daily bar histories between restarts
+type Store interface {
+	Load(ctx context.Context, ticker string) (Series, bool, error)
+	Save(ctx context.Context, series Series) error
+}
+
+// LastDate returns the date of the most recent bar, or "" for an empty series
+func (s Series) LastDate() string {
+	if len(s.Points) == 0 {
+		return ""
+	}
+	return s.Points[len(s.Points)-1].Date
+}
+
+// CoveredThrough returns the last date for which the series is known to be
+// complete. Bars before the day of the last fetch are final, so the series
+// covers at least the day before FetchedAt even when no bar exists for it,
+// as happens on weekends and holidays.
+func (s Series) CoveredThrough() string {
+	covered := s.LastDate()
+	if s.FetchedAt.IsZero() {
+		return covered
+	}
+	if dayBefore := s.FetchedAt.UTC().AddDate(0, 0, -1).Format(DateLayout); dayBefore > covered {
+		covered = dayBefore
+	}
+	return covered
+}
+
+// Range returns the bars between fromDate and toDate inclusive. Empty
+// bounds are open.
+func (s Series) Range(fromDate, toDate string) []types.HistoricalPricePoint {
+	start := 0
+	if fromDate != "" {
+		start = sort.Search(len(s.Points), func(i int) bool { return s.Points[i].Date >= fromDate })
+	}
+	end := len(s.Points)
+	if toDate != "" {
+		end = sort.Search(len(s.Points), func(i int) bool { return s.Points[i].Date > toDate })
+	}
+	if start >= end {
+		return []types.HistoricalPricePoint{}
+	}
+
+	points := make([]types.HistoricalPricePoint, end-start)
+	copy(points, s.Points[start:end])
+	return points
+}
+
+// Merge combines two sets of bars into one sorted by date. Where both hold
+// a bar for the same date, the one from newer wins.
+//
+// A split or dividend in newer changes the adjusted close of every earlier
+// bar, so older bars missing from newer are rescaled by how the adjusted
+// close of the first bar both hold has moved.
+func Merge(older, newer []types.HistoricalPricePoint) []types.HistoricalPricePoint {
+	byDate := make(map[string]types.HistoricalPricePoint, len(older)+len(newer))
+	for _, p := range older {
+		byDate[p.Date] = p
+	}
+
+	if len(newer) > 0 {
+		first := newer[0]
+		for _, p := range newer[1:] {
+			if p.Date < first.Date {
+				first = p
+			}
+		}
+		if previous, ok := byDate[first.Date]; ok && previous.AdjustedClose > 0 && first.AdjustedClose > 0 {
+			factor := first.AdjustedClose / previous.AdjustedClose
+			for date, p := range byDate {
+				p.AdjustedClose *= factor
+				byDate[date] = p
+			}
+		}
+	}
+
+	for _, p := range newer {
+		byDate[p.Date] = p
+	}
+
+	merged := make([]types.HistoricalPricePoint, 0, len(byDate))
+	for _, p := range byDate {
+		merged = append(merged, p)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Date < merged[j].Date })
+	return merged
+}
+
+// FileStore keeps one JSON file per ticker in a directory. Files are
+// replaced atomically so a crash never leaves a partial history behind.
+type FileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileStore creates a store under dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load returns the series stored for ticker
+func (s *FileStore) Load(ctx context.Context, ticker string) (Series, bool, error) {
+	data, err := os.ReadFile(s.path(ticker))
+	if errors.Is(err, os.ErrNotExist) {
+		return Series{}, false, nil
+	}
+	if err != nil {
+		return Series{}, false, fmt.Errorf("failed to read history for %s: %w", ticker, err)
+	}
+
+	var series Series
+	if err := json.Unmarshal(data, &series); err != nil {
+		return Series{}, false, fmt.Errorf("failed to decode history for %s: %w", ticker, err)
+	}
+	return series, true, nil
+}
+
+// Save replaces the series stored for its ticker
+func (s *FileStore) Save(ctx context.Context, series Series) error {
+	data, err := json.Marshal(series)
+	if err != nil {
+		return fmt.Errorf("failed to encode history for %s: %w", series.Ticker, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".history-*")
+	if err != nil {
+		return fmt.Errorf("failed to write history for %s: %w", series.Ticker, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history for %s: %w", series.Ticker, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history for %s: %w", series.Ticker, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write history for %s: %w", series.Ticker, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(series.Ticker)); err != nil {
+		return fmt.Errorf("failed to write history for %s: %w", series.Ticker, err)
+	}
+	return nil
+}
+
+// path returns the file holding ticker, escaped so any ticker maps to a
+// file inside the store directory
+func (s *FileStore) path(ticker string) string {
+	return filepath.Join(s.dir, url.PathEscape(ticker)+".json")
+}
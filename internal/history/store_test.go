@@ -0,0 +1,184 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+func bars(dates ...string) []types.HistoricalPricePoint {
+	points := make([]types.HistoricalPricePoint, len(dates))
+	for i, date := range dates {
+		points[i] = types.HistoricalPricePoint{Date: date, Close: float64(i + 1)}
+	}
+	return points
+}
+
+func dates(points []types.HistoricalPricePoint) []string {
+	out := make([]string, len(points))
+	for i, p := range points {
+		out[i] = p.Date
+	}
+	return out
+}
+
+func equalDates(got []types.HistoricalPricePoint, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if got[i].Date != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSeries_Range(t *testing.T) {
+	series := Series{Points: bars("2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05")}
+
+	tests := []struct {
+		name     string
+		fromDate string
+		toDate   string
+		want     []string
+	}{
+		{name: "Open bounds", want: []string{"2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05"}},
+		{name: "Inclusive bounds", fromDate: "2024-01-03", toDate: "2024-01-04", want: []string{"2024-01-03", "2024-01-04"}},
+		{name: "Bounds between bars", fromDate: "2024-01-01", toDate: "2024-01-03", want: []string{"2024-01-02", "2024-01-03"}},
+		{name: "Empty range", fromDate: "2024-02-01", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := series.Range(tt.fromDate, tt.toDate)
+			if !equalDates(got, tt.want) {
+				t.Errorf("Range() = %v, want %v", dates(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSeries_CoveredThrough(t *testing.T) {
+	series := Series{
+		Points:    bars("2024-01-04", "2024-01-05"),
+		FetchedAt: time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC),
+	}
+
+	// Fetched on Monday, so the weekend without bars is covered too
+	if got := series.CoveredThrough(); got != "2024-01-07" {
+		t.Errorf("CoveredThrough() = %q, want %q", got, "2024-01-07")
+	}
+
+	series.FetchedAt = time.Time{}
+	if got := series.CoveredThrough(); got != "2024-01-05" {
+		t.Errorf("CoveredThrough() without fetch time = %q, want %q", got, "2024-01-05")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	older := bars("2024-01-02", "2024-01-03")
+	newer := []types.HistoricalPricePoint{
+		{Date: "2024-01-04", Close: 30},
+		{Date: "2024-01-03", Close: 20},
+	}
+
+	merged := Merge(older, newer)
+	if !equalDates(merged, []string{"2024-01-02", "2024-01-03", "2024-01-04"}) {
+		t.Fatalf("Merge() = %v", dates(merged))
+	}
+	if merged[1].Close != 20 {
+		t.Errorf("Merge() kept close %v for overlapping date, want newer 20", merged[1].Close)
+	}
+}
+
+func TestMerge_RescalesAdjustedCloses(t *testing.T) {
+	older := []types.HistoricalPricePoint{
+		{Date: "2024-01-02", Close: 100, AdjustedClose: 100},
+		{Date: "2024-01-03", Close: 102, AdjustedClose: 102},
+	}
+	// A 2-for-1 split on 2024-01-04 halves every earlier adjusted close
+	newer := []types.HistoricalPricePoint{
+		{Date: "2024-01-03", Close: 102, AdjustedClose: 51},
+		{Date: "2024-01-04", Close: 52, AdjustedClose: 52, SplitCoefficient: 2},
+	}
+
+	merged := Merge(older, newer)
+	want := []float64{50, 51, 52}
+	if len(merged) != len(want) {
+		t.Fatalf("Merge() = %v", dates(merged))
+	}
+	for i, adjusted := range want {
+		if merged[i].AdjustedClose != adjusted {
+			t.Errorf("Merge()[%d].AdjustedClose = %v, want %v", i, merged[i].AdjustedClose, adjusted)
+		}
+	}
+	if merged[0].Close != 100 {
+		t.Errorf("Merge() changed the raw close to %v", merged[0].Close)
+	}
+}
+
+func TestFileStore_PersistsAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store, err := NewFileStore(filepath.Join(dir, "history"))
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	if _, found, err := store.Load(ctx, "AAPL"); err != nil || found {
+		t.Errorf("Load() on empty store = %v, %v; want not found", found, err)
+	}
+
+	want := Series{
+		Ticker:    "AAPL",
+		Points:    bars("2024-01-02", "2024-01-03"),
+		FetchedAt: time.Date(2024, 1, 3, 22, 0, 0, 0, time.UTC),
+	}
+	if err := store.Save(ctx, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := NewFileStore(filepath.Join(dir, "history"))
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	got, found, err := reopened.Load(ctx, "AAPL")
+	if err != nil || !found {
+		t.Fatalf("Load() = %v, %v", found, err)
+	}
+	if !equalDates(got.Points, dates(want.Points)) || !got.FetchedAt.Equal(want.FetchedAt) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFileStore_EscapesTickers(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	if err := store.Save(ctx, Series{Ticker: "../BRK/B", Points: bars("2024-01-02")}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].IsDir() {
+		t.Fatalf("Expected a single file inside the store directory, got %v", entries)
+	}
+
+	if _, found, _ := store.Load(ctx, "../BRK/B"); !found {
+		t.Error("Expected escaped ticker to load back")
+	}
+}
@@ -0,0 +1,317 @@
+package pricehub
+
+import (
+	"context"
+	"expvar"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+)
+
+// metrics reports the number of subscribers and polled tickers, polls made,
+// updates delivered and subscribers dropped for falling behind.
+// subscriberMetrics counts the subscribers of each ticker; tickers are
+// removed once nobody is subscribed to them, so it only grows with the
+// tickers in use.
+var (
+	metrics           = expvar.NewMap("price_hub")
+	subscriberMetrics = expvar.NewMap("price_hub_subscribers")
+	// subscriberCounts mirrors subscriberMetrics so keys can be removed at
+	// zero; it is shared by every hub
+	subscriberCounts      = make(map[string]int64)
+	subscriberCountsMutex sync.Mutex
+)
+
+// countSubscriber adjusts the subscriber count of a ticker
+func countSubscriber(ticker string, delta int64) {
+	subscriberCountsMutex.Lock()
+	defer subscriberCountsMutex.Unlock()
+
+	subscriberCounts[ticker] += delta
+	if subscriberCounts[ticker] <= 0 {
+		delete(subscriberCounts, ticker)
+		subscriberMetrics.Delete(ticker)
+		return
+	}
+	subscriberMetrics.Add(ticker, delta)
+}
+
+// defaultBufferSize is the number of updates a subscriber may fall behind
+// by when Options.BufferSize is not set
+const defaultBufferSize = 64
+
+// ErrSlowConsumer is the reason a subscriber that fell behind was dropped
+var ErrSlowConsumer = apperror.New(apperror.ErrRateLimited, "stream dropped: updates were not read fast enough")
+
+// Options controls how far subscribers may fall behind and how tickers are
+// polled
+type Options struct {
+	// BufferSize is the number of unread updates a subscriber may have
+	// before it is dropped
+	BufferSize int
+	// MinInterval and MaxInterval bound poll intervals. Zero leaves an
+	// interval unbounded on that side.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Jitter spreads polls of tickers sharing an interval: each wait is
+	// the interval varied by up to this fraction either way, so 0.1 means
+	// ±10%
+	Jitter float64
+}
+
+// Update is one poll of a ticker. Err is set when its price could not be
+// fetched.
+type Update struct {
+	Ticker   string
+	Interval time.Duration
+	Price    float64
+	Time     time.Time
+	Meta     service.TickerMeta
+	Err      error
+}
+
+// Hub polls the price of each ticker subscribed to once per interval,
+// however many subscribers share it, and fans the result out to them. A
+// ticker is polled only while it has subscribers.
+type Hub struct {
+	svc    service.PriceService
+	opts   Options
+	mutex  sync.Mutex
+	topics map[topicKey]*topic
+}
+
+// topicKey identifies one poll loop: a ticker polled at an interval
+type topicKey struct {
+	ticker   string
+	interval time.Duration
+}
+
+type topic struct {
+	key         topicKey
+	subscribers map[*Subscriber]struct{}
+	stop        context.CancelFunc
+}
+
+// Subscriber receives the updates of the tickers added to it on one
+// bounded channel. A subscriber whose buffer is full when an update is
+// published is dropped.
+type Subscriber struct {
+	hub     *Hub
+	updates chan Update
+	dropped chan struct{}
+
+	// Guarded by hub.mutex
+	topics map[string]topicKey
+	closed bool
+}
+
+// NewHub creates a hub polling svc
+func NewHub(svc service.PriceService, opts Options) *Hub {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultBufferSize
+	}
+	return &Hub{svc: svc, opts: opts, topics: make(map[topicKey]*topic)}
+}
+
+// Subscribe creates a subscriber with no tickers. It must be closed when
+// no longer read.
+func (h *Hub) Subscribe() *Subscriber {
+	metrics.Add("subscribers", 1)
+	return &Subscriber{
+		hub:     h,
+		updates: make(chan Update, h.opts.BufferSize),
+		dropped: make(chan struct{}),
+		topics:  make(map[string]topicKey),
+	}
+}
+
+// Updates returns the channel updates are delivered on
+func (s *Subscriber) Updates() <-chan Update {
+	return s.updates
+}
+
+// Dropped returns a channel closed when the subscriber is dropped for
+// falling behind. No updates are delivered after that.
+func (s *Subscriber) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+// ValidateInterval checks that a poll interval requested by a client is
+// within the hub's bounds
+func (h *Hub) ValidateInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return apperror.InvalidArgument("interval must be positive")
+	}
+	if h.opts.MinInterval > 0 && interval < h.opts.MinInterval {
+		return apperror.InvalidArgument("interval must be at least %v", h.opts.MinInterval)
+	}
+	if h.opts.MaxInterval > 0 && interval > h.opts.MaxInterval {
+		return apperror.InvalidArgument("interval must be at most %v", h.opts.MaxInterval)
+	}
+	return nil
+}
+
+// ClampInterval brings an interval within the hub's bounds
+func (h *Hub) ClampInterval(interval time.Duration) time.Duration {
+	if h.opts.MinInterval > 0 && interval < h.opts.MinInterval {
+		return h.opts.MinInterval
+	}
+	if h.opts.MaxInterval > 0 && interval > h.opts.MaxInterval {
+		return h.opts.MaxInterval
+	}
+	return interval
+}
+
+// Fetch fetches a ticker's current price outside its poll loop, such as
+// for a snapshot sent when a stream starts
+func (h *Hub) Fetch(ctx context.Context, ticker string) Update {
+	return h.fetch(ctx, topicKey{ticker: ticker})
+}
+
+// Add subscribes to a ticker's price every interval, which must be
+// positive. Intervals outside the hub's bounds are clamped to them. Adding a
+// ticker again changes its interval.
+func (s *Subscriber) Add(ticker string, interval time.Duration) {
+	h := s.hub
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+	key := topicKey{ticker: ticker, interval: h.ClampInterval(interval)}
+	if current, ok := s.topics[ticker]; ok {
+		if current == key {
+			return
+		}
+		h.leaveLocked(s, current)
+	}
+
+	t, ok := h.topics[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		t = &topic{key: key, subscribers: make(map[*Subscriber]struct{}), stop: cancel}
+		h.topics[key] = t
+		metrics.Add("topics", 1)
+		go h.poll(ctx, t)
+	}
+	t.subscribers[s] = struct{}{}
+	s.topics[ticker] = key
+	countSubscriber(ticker, 1)
+}
+
+// Remove unsubscribes from a ticker
+func (s *Subscriber) Remove(ticker string) {
+	h := s.hub
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if key, ok := s.topics[ticker]; ok {
+		h.leaveLocked(s, key)
+	}
+}
+
+// Close unsubscribes from every ticker. Closing twice is a no-op.
+func (s *Subscriber) Close() {
+	h := s.hub
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.closeLocked(s)
+}
+
+// leaveLocked removes a subscriber from a topic, stopping the topic's poll
+// loop once it has no subscribers left
+func (h *Hub) leaveLocked(s *Subscriber, key topicKey) {
+	delete(s.topics, key.ticker)
+	countSubscriber(key.ticker, -1)
+
+	t, ok := h.topics[key]
+	if !ok {
+		return
+	}
+	delete(t.subscribers, s)
+	if len(t.subscribers) == 0 {
+		t.stop()
+		delete(h.topics, key)
+		metrics.Add("topics", -1)
+	}
+}
+
+func (h *Hub) closeLocked(s *Subscriber) {
+	if s.closed {
+		return
+	}
+	for _, key := range s.topics {
+		h.leaveLocked(s, key)
+	}
+	s.closed = true
+	metrics.Add("subscribers", -1)
+}
+
+// poll fetches a topic's price every interval, varied by the jitter, until
+// the topic is stopped
+func (h *Hub) poll(ctx context.Context, t *topic) {
+	timer := time.NewTimer(h.wait(t.key.interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			update := h.fetch(ctx, t.key)
+			if ctx.Err() != nil {
+				return
+			}
+			h.publish(t, update)
+			timer.Reset(h.wait(t.key.interval))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// wait returns the time until a topic's next poll
+func (h *Hub) wait(interval time.Duration) time.Duration {
+	if h.opts.Jitter <= 0 {
+		return interval
+	}
+	spread := float64(interval) * h.opts.Jitter
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+func (h *Hub) fetch(ctx context.Context, key topicKey) Update {
+	metrics.Add("polls", 1)
+	ctx, meta := service.WithResponseMeta(ctx)
+	price, err := h.svc.FetchPrice(ctx, key.ticker)
+	if err != nil {
+		return Update{Ticker: key.ticker, Interval: key.interval, Time: time.Now(), Err: err}
+	}
+	return Update{
+		Ticker:   key.ticker,
+		Interval: key.interval,
+		Price:    price,
+		Time:     time.Now(),
+		Meta:     meta.Ticker(key.ticker),
+	}
+}
+
+// publish delivers an update to every subscriber of a topic without
+// blocking, dropping subscribers whose buffer is full
+func (h *Hub) publish(t *topic, update Update) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for s := range t.subscribers {
+		select {
+		case s.updates <- update:
+			metrics.Add("delivered", 1)
+		default:
+			h.closeLocked(s)
+			close(s.dropped)
+			metrics.Add("dropped", 1)
+		}
+	}
+}
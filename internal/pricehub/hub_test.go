@@ -0,0 +1,258 @@
+package pricehub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+)
+
+// countingService counts the price fetches of each ticker
+type countingService struct {
+	service.PriceService
+	mutex   sync.Mutex
+	fetches map[string]int
+}
+
+func newCountingService() *countingService {
+	return &countingService{PriceService: service.NewPriceService(), fetches: make(map[string]int)}
+}
+
+func (s *countingService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	s.mutex.Lock()
+	s.fetches[ticker]++
+	s.mutex.Unlock()
+	return s.PriceService.FetchPrice(ctx, ticker)
+}
+
+func (s *countingService) count(ticker string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.fetches[ticker]
+}
+
+func receive(t *testing.T, sub *Subscriber) Update {
+	t.Helper()
+	select {
+	case u := <-sub.Updates():
+		return u
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an update")
+		return Update{}
+	}
+}
+
+func TestHub_FanOut(t *testing.T) {
+	svc := newCountingService()
+	hub := NewHub(svc, Options{})
+
+	subscribers := make([]*Subscriber, 3)
+	for i := range subscribers {
+		subscribers[i] = hub.Subscribe()
+		subscribers[i].Add("AAPL", 10*time.Millisecond)
+		defer subscribers[i].Close()
+	}
+
+	for round := 0; round < 3; round++ {
+		for _, sub := range subscribers {
+			if u := receive(t, sub); u.Ticker != "AAPL" || u.Price != 150 || u.Err != nil {
+				t.Errorf("update = %+v, want AAPL at 150", u)
+			}
+		}
+	}
+
+	// Every subscriber got 3 updates from about 3 polls, not 9
+	if polls := svc.count("AAPL"); polls < 3 || polls > 6 {
+		t.Errorf("AAPL polled %d times, want one poll per interval", polls)
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if len(hub.topics) != 1 {
+		t.Errorf("%d topics, want 1 shared by every subscriber", len(hub.topics))
+	}
+}
+
+func TestHub_StopsPollingWithoutSubscribers(t *testing.T) {
+	svc := newCountingService()
+	hub := NewHub(svc, Options{})
+
+	sub := hub.Subscribe()
+	sub.Add("AAPL", 5*time.Millisecond)
+	sub.Add("GOOGL", 5*time.Millisecond)
+	receive(t, sub)
+
+	sub.Remove("AAPL")
+	sub.Close()
+	sub.Close()
+
+	// Allow a poll in flight to finish before counting
+	time.Sleep(20 * time.Millisecond)
+	aapl, googl := svc.count("AAPL"), svc.count("GOOGL")
+	time.Sleep(30 * time.Millisecond)
+	if svc.count("AAPL") != aapl || svc.count("GOOGL") != googl {
+		t.Error("tickers still polled after every subscriber left")
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if len(hub.topics) != 0 {
+		t.Errorf("%d topics left, want 0", len(hub.topics))
+	}
+}
+
+func TestHub_Intervals(t *testing.T) {
+	hub := NewHub(newCountingService(), Options{})
+
+	fast, slow := hub.Subscribe(), hub.Subscribe()
+	defer fast.Close()
+	defer slow.Close()
+	fast.Add("AAPL", 10*time.Millisecond)
+	slow.Add("AAPL", time.Hour)
+
+	if u := receive(t, fast); u.Interval != 10*time.Millisecond {
+		t.Errorf("Interval = %v, want 10ms", u.Interval)
+	}
+
+	// Adding a ticker again moves it to the new interval
+	slow.Add("AAPL", 10*time.Millisecond)
+	receive(t, slow)
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if len(hub.topics) != 1 {
+		t.Errorf("%d topics, want 1 once both share an interval", len(hub.topics))
+	}
+}
+
+func TestHub_Errors(t *testing.T) {
+	hub := NewHub(newCountingService(), Options{})
+
+	sub := hub.Subscribe()
+	defer sub.Close()
+	sub.Add("ZZZZ", 5*time.Millisecond)
+
+	if u := receive(t, sub); !errors.Is(u.Err, apperror.ErrNotFound) {
+		t.Errorf("update error = %v, want not found", u.Err)
+	}
+}
+
+func TestHub_DropsSlowConsumers(t *testing.T) {
+	hub := NewHub(newCountingService(), Options{BufferSize: 1})
+
+	slow, fast := hub.Subscribe(), hub.Subscribe()
+	defer fast.Close()
+	slow.Add("AAPL", 5*time.Millisecond)
+	fast.Add("AAPL", 5*time.Millisecond)
+
+	// Only the subscriber that stops reading is dropped
+	timeout := time.After(time.Second)
+	for dropped := false; !dropped; {
+		select {
+		case <-fast.Updates():
+		case <-slow.Dropped():
+			dropped = true
+		case <-timeout:
+			t.Fatal("slow subscriber was not dropped")
+		}
+	}
+	for i := 0; i < 3; i++ {
+		receive(t, fast)
+	}
+	select {
+	case <-fast.Dropped():
+		t.Error("subscriber reading its updates was dropped")
+	default:
+	}
+
+	// A dropped subscriber can still be closed
+	slow.Close()
+}
+
+func TestHub_IntervalBounds(t *testing.T) {
+	hub := NewHub(newCountingService(), Options{MinInterval: 10 * time.Millisecond, MaxInterval: time.Minute})
+
+	for _, tt := range []struct {
+		interval time.Duration
+		valid    bool
+	}{
+		{0, false},
+		{time.Millisecond, false},
+		{10 * time.Millisecond, true},
+		{time.Minute, true},
+		{time.Hour, false},
+	} {
+		if err := hub.ValidateInterval(tt.interval); (err == nil) != tt.valid {
+			t.Errorf("ValidateInterval(%v) = %v, want valid %v", tt.interval, err, tt.valid)
+		} else if err != nil && !errors.Is(err, apperror.ErrInvalidArgument) {
+			t.Errorf("ValidateInterval(%v) = %v, want invalid argument", tt.interval, err)
+		}
+	}
+
+	// Intervals outside the bounds are clamped to them
+	sub := hub.Subscribe()
+	defer sub.Close()
+	sub.Add("AAPL", time.Nanosecond)
+	sub.Add("GOOGL", 24*time.Hour)
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if got := sub.topics["AAPL"].interval; got != 10*time.Millisecond {
+		t.Errorf("AAPL interval = %v, want 10ms", got)
+	}
+	if got := sub.topics["GOOGL"].interval; got != time.Minute {
+		t.Errorf("GOOGL interval = %v, want 1m", got)
+	}
+}
+
+func TestHub_Jitter(t *testing.T) {
+	if got := NewHub(newCountingService(), Options{}).wait(time.Second); got != time.Second {
+		t.Errorf("wait without jitter = %v, want 1s", got)
+	}
+
+	hub := NewHub(newCountingService(), Options{Jitter: 0.1})
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := hub.wait(time.Second)
+		if got < 900*time.Millisecond || got > 1100*time.Millisecond {
+			t.Fatalf("wait = %v, want within 10%% of 1s", got)
+		}
+		varied = varied || got != time.Second
+	}
+	if !varied {
+		t.Error("wait never varied with jitter")
+	}
+}
+
+func TestHub_Fetch(t *testing.T) {
+	svc := newCountingService()
+	hub := NewHub(svc, Options{})
+
+	if u := hub.Fetch(context.Background(), "AAPL"); u.Ticker != "AAPL" || u.Price != 150 || u.Err != nil {
+		t.Errorf("Fetch = %+v, want AAPL at 150", u)
+	}
+	if svc.count("AAPL") != 1 {
+		t.Errorf("AAPL fetched %d times, want 1", svc.count("AAPL"))
+	}
+}
+
+func TestHub_SubscriberMetrics(t *testing.T) {
+	hub := NewHub(newCountingService(), Options{})
+
+	first, second := hub.Subscribe(), hub.Subscribe()
+	first.Add("METRIC1", time.Hour)
+	second.Add("METRIC1", time.Hour)
+	if got := subscriberMetrics.Get("METRIC1"); got == nil || got.String() != "2" {
+		t.Errorf("METRIC1 subscribers = %v, want 2", got)
+	}
+
+	first.Close()
+	second.Close()
+	if got := subscriberMetrics.Get("METRIC1"); got != nil {
+		t.Errorf("METRIC1 subscribers = %v, want the ticker removed", got)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/http"
+)
+
+// AdminServer serves operational endpoints, such as the expvar counters,
+// on a listener of their own so they are not exposed with the public API
+type AdminServer struct {
+	listenAddr string
+	server     *http.Server
+}
+
+func NewAdminServer(listenAddr string) *AdminServer {
+	return &AdminServer{listenAddr: listenAddr}
+}
+
+func (s *AdminServer) Run() error {
+	s.server = &http.Server{
+		Addr:    s.listenAddr,
+		Handler: s.Handler(),
+	}
+
+	fmt.Println("Admin server started on", s.listenAddr)
+	return s.server.ListenAndServe()
+}
+
+// Handler returns the admin routes
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+func (s *AdminServer) Shutdown(ctx context.Context) error {
+	if s.server != nil {
+		return s.server.Shutdown(ctx)
+	}
+	return nil
+}
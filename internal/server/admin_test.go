@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminServer_DebugVars(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewAdminServer(":0").Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("admin /debug/vars status = %d, want 200", rec.Code)
+	}
+
+	// The counters are not served on the public API
+	rec = httptest.NewRecorder()
+	NewJSONAPIServer(":0", nil, nil, nil, nil, nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("JSON API /debug/vars status = %d, want 404", rec.Code)
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"net"
 	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
 	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
 	"github.com/aliexe/ms-priceFetcher/proto"
+	protov2 "github.com/aliexe/ms-priceFetcher/proto/v2"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -14,6 +18,7 @@ import (
 
 type GRPCPriceFetcherServer struct {
 	svc service.PriceService
+	hub *pricehub.Hub
 	proto.UnimplementedPriceFetcherServer
 }
 
@@ -22,14 +27,15 @@ type GRPCServer struct {
 	listener net.Listener
 }
 
-func MakeGRPCServer(listenAddr string, svc service.PriceService) (*GRPCServer, error) {
+func MakeGRPCServer(listenAddr string, svc service.PriceService, alertSvc *service.AlertService, rates service.RateService, hub *pricehub.Hub) (*GRPCServer, error) {
 	ln, err := net.Listen("tcp", listenAddr)
 	if err != nil {
 		return nil, err
 	}
 
 	server := grpc.NewServer()
-	proto.RegisterPriceFetcherServer(server, NewGRPCPriceFetcherServer(svc))
+	proto.RegisterPriceFetcherServer(server, NewGRPCPriceFetcherServer(svc, hub))
+	protov2.RegisterPriceFetcherServer(server, NewGRPCPriceFetcherServerV2(svc, alertSvc, rates, hub))
 	reflection.Register(server)
 
 	return &GRPCServer{
@@ -46,20 +52,64 @@ func (s *GRPCServer) Stop() {
 	s.server.GracefulStop()
 }
 
-func NewGRPCPriceFetcherServer(svc service.PriceService) *GRPCPriceFetcherServer {
-	return &GRPCPriceFetcherServer{svc: svc}
+func NewGRPCPriceFetcherServer(svc service.PriceService, hub *pricehub.Hub) *GRPCPriceFetcherServer {
+	return &GRPCPriceFetcherServer{svc: svc, hub: hub}
 }
 
 func (s *GRPCPriceFetcherServer) FetchPrice(ctx context.Context, req *proto.FetchPriceRequest) (*proto.FetchPriceResponse, error) {
 	reqID := uuid.New().ID()
 	ctx = context.WithValue(ctx, "requestID", reqID)
-	price, err := s.svc.FetchPrice(ctx, req.Ticker)
+	ticker, err := symbol.Normalize(req.Ticker)
 	if err != nil {
-		return nil, err
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	price, err := s.svc.FetchPrice(ctx, ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
 	}
+	tickerMeta := meta.Ticker(ticker)
 	resp := &proto.FetchPriceResponse{
-		Ticker: req.Ticker,
-		Price:  float32(price),
+		Ticker:   ticker,
+		Price:    float32(price),
+		Currency: tickerCurrency(ticker),
+		Source:   tickerMeta.Source,
+		Stale:    tickerMeta.Stale,
+		AsOf:     formatAsOf(tickerMeta.AsOf),
+	}
+	return resp, nil
+}
+
+func (s *GRPCPriceFetcherServer) FetchQuote(ctx context.Context, req *proto.FetchQuoteRequest) (*proto.FetchQuoteResponse, error) {
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+	ticker, err := symbol.Normalize(req.Ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	quote, err := s.svc.FetchQuote(ctx, ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	tickerMeta := meta.Ticker(ticker)
+	resp := &proto.FetchQuoteResponse{
+		Ticker:           quote.Ticker,
+		Open:             quote.Open,
+		High:             quote.High,
+		Low:              quote.Low,
+		Price:            quote.Price,
+		Volume:           quote.Volume,
+		LatestTradingDay: quote.LatestTradingDay,
+		PreviousClose:    quote.PreviousClose,
+		Change:           quote.Change,
+		ChangePercent:    quote.ChangePercent,
+		Currency:         tickerCurrency(ticker),
+		Source:           tickerMeta.Source,
+		Stale:            tickerMeta.Stale,
+		AsOf:             formatAsOf(tickerMeta.AsOf),
 	}
 	return resp, nil
 }
@@ -69,50 +119,112 @@ func (s *GRPCPriceFetcherServer) StreamPrices(req *proto.StreamPricesRequest, st
 	reqID := uuid.New().ID()
 	ctx = context.WithValue(ctx, "requestID", reqID)
 
-	// Default interval to 5 seconds if not specified
-	interval := time.Duration(req.IntervalSeconds)
-	if interval == 0 {
-		interval = 5 * time.Second
+	tickers, err := normalizeTickers(req.Tickers)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+
+	intervals, err := parseStreamIntervals(s.hub, tickers, req.IntervalSeconds, req.TickerIntervalSeconds)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+
+	opts, err := parseStreamOptions(req.Mode, req.MinDeltaAmount, req.MinDeltaPercent, req.KeepaliveSeconds)
+	if err != nil {
+		return apperror.GRPCStatus(err)
 	}
+	opts.snapshotFirst = req.SnapshotFirst
+
+	return streamPrices(ctx, s.hub, tickers, intervals, opts, func(u streamUpdate) error {
+		if u.keepalive {
+			return stream.Send(&proto.StreamPricesResponse{Timestamp: u.Time.Format(time.RFC3339), Keepalive: true})
+		}
 
-	doneChan := make(chan struct{})
-
-	// Start price update goroutine
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Send updates for all requested tickers
-				for _, t := range req.Tickers {
-					price, err := s.svc.FetchPrice(ctx, t)
-					if err != nil {
-						continue
-					}
-
-					resp := &proto.StreamPricesResponse{
-						Ticker:    t,
-						Price:     float32(price),
-						Timestamp: time.Now().Format(time.RFC3339),
-					}
-
-					if err := stream.Send(resp); err != nil {
-						return
-					}
-				}
-			case <-doneChan:
-				return
-			case <-ctx.Done():
-				return
+		resp := &proto.StreamPricesResponse{
+			Ticker:    u.Ticker,
+			Price:     float32(u.Price),
+			Timestamp: u.Time.Format(time.RFC3339),
+			Currency:  tickerCurrency(u.Ticker),
+			Source:    u.Meta.Source,
+			Stale:     u.Meta.Stale,
+			AsOf:      formatAsOf(u.Meta.AsOf),
+		}
+		if u.hasPrevious {
+			change, changePercent := u.change()
+			resp.PreviousPrice = float32(u.previous)
+			resp.Change = float32(change)
+			resp.ChangePercent = changePercent
+		}
+		return stream.Send(resp)
+	})
+}
+
+// streamPrices subscribes to the tickers on the hub and passes the prices
+// picked by the stream's mode to send, with keepalives while idle, until
+// ctx is done, send fails or the stream falls behind and is dropped.
+// Tickers whose price cannot be fetched are skipped until the next poll.
+func streamPrices(ctx context.Context, hub *pricehub.Hub, tickers []string, intervals streamIntervals, opts streamOptions, send func(streamUpdate) error) error {
+	sub := hub.Subscribe()
+	defer sub.Close()
+	for _, t := range tickers {
+		sub.Add(t, intervals.of(t))
+	}
+
+	filter := newPriceFilter(opts)
+	if opts.snapshotFirst {
+		snapshotted := make(map[string]bool, len(tickers))
+		for _, t := range tickers {
+			if snapshotted[t] {
+				continue
+			}
+			snapshotted[t] = true
+			u := hub.Fetch(ctx, t)
+			if u.Err != nil {
+				continue
+			}
+			update, _ := filter.next(u)
+			if err := send(update); err != nil {
+				return err
 			}
 		}
-	}()
+	}
 
-	// Keep streaming until client disconnects
-	<-ctx.Done()
-	close(doneChan)
+	var keepalive <-chan time.Time
+	var keepaliveTimer *time.Timer
+	if opts.keepalive > 0 {
+		keepaliveTimer = time.NewTimer(opts.keepalive)
+		defer keepaliveTimer.Stop()
+		keepalive = keepaliveTimer.C
+	}
+	resetKeepalive := func() {
+		if keepaliveTimer != nil {
+			keepaliveTimer.Reset(opts.keepalive)
+		}
+	}
 
-	return nil
+	for {
+		select {
+		case u := <-sub.Updates():
+			if u.Err != nil {
+				continue
+			}
+			update, ok := filter.next(u)
+			if !ok {
+				continue
+			}
+			if err := send(update); err != nil {
+				return err
+			}
+			resetKeepalive()
+		case <-keepalive:
+			if err := send(streamUpdate{Update: pricehub.Update{Time: time.Now()}, keepalive: true}); err != nil {
+				return err
+			}
+			resetKeepalive()
+		case <-sub.Dropped():
+			return apperror.GRPCStatus(pricehub.ErrSlowConsumer)
+		case <-ctx.Done():
+			return nil
+		}
+	}
 }
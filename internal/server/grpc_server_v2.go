@@ -0,0 +1,505 @@
+package server
+
+import (
+	"context"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	protov2 "github.com/aliexe/ms-priceFetcher/proto/v2"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCPriceFetcherServerV2 serves the v2 API, which carries prices as exact
+// money amounts rather than float32
+type GRPCPriceFetcherServerV2 struct {
+	svc      service.PriceService
+	alertSvc *service.AlertService
+	rates    service.RateService
+	hub      *pricehub.Hub
+	protov2.UnimplementedPriceFetcherServer
+}
+
+func NewGRPCPriceFetcherServerV2(svc service.PriceService, alertSvc *service.AlertService, rates service.RateService, hub *pricehub.Hub) *GRPCPriceFetcherServerV2 {
+	return &GRPCPriceFetcherServerV2{svc: svc, alertSvc: alertSvc, rates: rates, hub: hub}
+}
+
+func (s *GRPCPriceFetcherServerV2) FetchPrice(ctx context.Context, req *protov2.FetchPriceRequest) (*protov2.FetchPriceResponse, error) {
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+	ticker, err := symbol.Normalize(req.Ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	price, err := s.svc.FetchPrice(ctx, ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	tickerMeta := meta.Ticker(ticker)
+	resp := &protov2.FetchPriceResponse{
+		Ticker: ticker,
+		Price:  toMoney(price, tickerCurrency(ticker)),
+		Source: tickerMeta.Source,
+		Stale:  tickerMeta.Stale,
+		AsOf:   toTimestamp(tickerMeta.AsOf),
+	}
+	return resp, nil
+}
+
+func (s *GRPCPriceFetcherServerV2) FetchPrices(ctx context.Context, req *protov2.FetchPricesRequest) (*protov2.FetchPricesResponse, error) {
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+	tickers, err := normalizeTickers(req.Tickers)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	currency, err := parseCurrency(req.Currency, s.rates)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	rateCtx := ctx
+	ctx, meta := service.WithResponseMeta(ctx)
+	prices, err := s.svc.FetchPrices(ctx, tickers)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	currencies, conversionErrors, err := convertPrices(rateCtx, s.rates, prices, currency)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	// Prices are listed in request order, once per ticker
+	resp := &protov2.FetchPricesResponse{Errors: conversionErrors}
+	seen := make(map[string]bool, len(tickers))
+	for _, ticker := range tickers {
+		price, ok := prices[ticker]
+		if !ok || seen[ticker] {
+			continue
+		}
+		seen[ticker] = true
+
+		tickerMeta := meta.Ticker(ticker)
+		resp.Prices = append(resp.Prices, &protov2.TickerPrice{
+			Ticker: ticker,
+			Price:  toMoney(price, currencies[ticker]),
+			Source: tickerMeta.Source,
+			Stale:  tickerMeta.Stale,
+			AsOf:   toTimestamp(tickerMeta.AsOf),
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCPriceFetcherServerV2) FetchQuote(ctx context.Context, req *protov2.FetchQuoteRequest) (*protov2.FetchQuoteResponse, error) {
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+	ticker, err := symbol.Normalize(req.Ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	quote, err := s.svc.FetchQuote(ctx, ticker)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	tickerMeta := meta.Ticker(ticker)
+	currency := tickerCurrency(ticker)
+	resp := &protov2.FetchQuoteResponse{
+		Ticker:           quote.Ticker,
+		Open:             toMoney(quote.Open, currency),
+		High:             toMoney(quote.High, currency),
+		Low:              toMoney(quote.Low, currency),
+		Price:            toMoney(quote.Price, currency),
+		Volume:           quote.Volume,
+		LatestTradingDay: quote.LatestTradingDay,
+		PreviousClose:    toMoney(quote.PreviousClose, currency),
+		Change:           toMoney(quote.Change, currency),
+		ChangePercent:    quote.ChangePercent,
+		Source:           tickerMeta.Source,
+		Stale:            tickerMeta.Stale,
+		AsOf:             toTimestamp(tickerMeta.AsOf),
+	}
+	return resp, nil
+}
+
+func (s *GRPCPriceFetcherServerV2) StreamPrices(req *protov2.StreamPricesRequest, stream protov2.PriceFetcher_StreamPricesServer) error {
+	ctx := stream.Context()
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+
+	tickers, err := normalizeTickers(req.Tickers)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+
+	intervals, err := parseStreamIntervals(s.hub, tickers, req.IntervalSeconds, req.TickerIntervalSeconds)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+
+	opts, err := parseStreamOptions(fromProtoStreamMode(req.Mode), req.MinDeltaAmount, req.MinDeltaPercent, req.KeepaliveSeconds)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+	opts.snapshotFirst = req.SnapshotFirst
+
+	return streamPrices(ctx, s.hub, tickers, intervals, opts, func(u streamUpdate) error {
+		if u.keepalive {
+			return stream.Send(&protov2.StreamPricesResponse{Timestamp: timestamppb.New(u.Time), Keepalive: true})
+		}
+
+		resp := toStreamPrice(u.Update)
+		if u.hasPrevious {
+			currency := resp.Price.GetCurrencyCode()
+			change, changePercent := u.change()
+			resp.PreviousPrice = toMoney(u.previous, currency)
+			resp.Change = toMoney(change, currency)
+			resp.ChangePercent = changePercent
+		}
+		return stream.Send(resp)
+	})
+}
+
+// subscribeInterval is how often Subscribe streams are sent prices, and
+// heartbeatInterval how long they may be idle before a heartbeat is sent
+var (
+	subscribeInterval = 5 * time.Second
+	heartbeatInterval = 15 * time.Second
+)
+
+func (s *GRPCPriceFetcherServerV2) Subscribe(stream protov2.PriceFetcher_SubscribeServer) error {
+	ctx := stream.Context()
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+
+	// Requests are received on their own goroutine so subscription changes
+	// are acknowledged while prices are being sent
+	requests := make(chan *protov2.SubscribeRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	session := newSubscriptionSession(s.hub, subscribeInterval)
+	defer session.close()
+
+	heartbeat := time.NewTimer(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	send := func(resp *protov2.SubscribeResponse) error {
+		heartbeat.Reset(heartbeatInterval)
+		return stream.Send(resp)
+	}
+
+	for {
+		select {
+		case req := <-requests:
+			var rejected []tickerError
+			switch action := req.Action.(type) {
+			case *protov2.SubscribeRequest_Subscribe:
+				rejected = session.subscribe(action.Subscribe.GetTickers())
+			case *protov2.SubscribeRequest_Unsubscribe:
+				rejected = session.unsubscribe(action.Unsubscribe.GetTickers())
+			default:
+				rejected = []tickerError{{err: errNoSubscriptionAction}}
+			}
+
+			ack := &protov2.SubscriptionAck{RequestId: req.RequestId, Tickers: session.set.list()}
+			for _, r := range rejected {
+				ack.Errors = append(ack.Errors, toTickerError(r.ticker, r.err))
+			}
+			if err := send(&protov2.SubscribeResponse{Message: &protov2.SubscribeResponse_Ack{Ack: ack}}); err != nil {
+				return err
+			}
+
+		case u := <-session.sub.Updates():
+			if !session.deliver(u) {
+				continue
+			}
+
+			resp := &protov2.SubscribeResponse{Message: &protov2.SubscribeResponse_Price{Price: toStreamPrice(u)}}
+			if u.Err != nil {
+				resp = &protov2.SubscribeResponse{Message: &protov2.SubscribeResponse_Error{Error: toTickerError(u.Ticker, u.Err)}}
+			}
+			if err := send(resp); err != nil {
+				return err
+			}
+
+		case <-session.sub.Dropped():
+			return apperror.GRPCStatus(pricehub.ErrSlowConsumer)
+
+		case <-heartbeat.C:
+			resp := &protov2.SubscribeResponse{Message: &protov2.SubscribeResponse_Heartbeat{
+				Heartbeat: &protov2.Heartbeat{Timestamp: timestamppb.Now()},
+			}}
+			if err := send(resp); err != nil {
+				return err
+			}
+
+		case err := <-recvErr:
+			// The client closing its side ends the stream
+			if err == io.EOF {
+				return nil
+			}
+			return err
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// toStreamPrice converts a hub update to a v2 stream price
+func toStreamPrice(u pricehub.Update) *protov2.StreamPricesResponse {
+	return &protov2.StreamPricesResponse{
+		Ticker:    u.Ticker,
+		Price:     toMoney(u.Price, tickerCurrency(u.Ticker)),
+		Timestamp: timestamppb.New(u.Time),
+		Source:    u.Meta.Source,
+		Stale:     u.Meta.Stale,
+		AsOf:      toTimestamp(u.Meta.AsOf),
+	}
+}
+
+// fromProtoStreamMode converts a v2 stream mode. Unknown modes are left for
+// parseStreamOptions to reject.
+func fromProtoStreamMode(mode protov2.StreamMode) string {
+	switch mode {
+	case protov2.StreamMode_STREAM_MODE_UNSPECIFIED, protov2.StreamMode_STREAM_MODE_EVERY_TICK:
+		return modeEveryTick
+	case protov2.StreamMode_STREAM_MODE_ON_CHANGE:
+		return modeOnChange
+	case protov2.StreamMode_STREAM_MODE_MIN_DELTA:
+		return modeMinDelta
+	default:
+		return mode.String()
+	}
+}
+
+// toTickerError converts a per-ticker error to its v2 form
+func toTickerError(ticker string, err error) *protov2.TickerError {
+	return &protov2.TickerError{
+		Ticker:  ticker,
+		Code:    apperror.GRPCCode(err).String(),
+		Message: err.Error(),
+	}
+}
+
+// historyChunkSize is the number of bars sent per FetchPriceHistory message
+const historyChunkSize = 500
+
+func (s *GRPCPriceFetcherServerV2) FetchPriceHistory(req *protov2.FetchPriceHistoryRequest, stream protov2.PriceFetcher_FetchPriceHistoryServer) error {
+	ctx := stream.Context()
+	reqID := uuid.New().ID()
+	ctx = context.WithValue(ctx, "requestID", reqID)
+
+	query, err := parseHistoryRequest(req.Ticker, req.Interval, req.Period, req.Adjusted, req.From, req.To, req.Currency, s.rates)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+	result, err := fetchHistory(ctx, s.svc, s.rates, query)
+	if err != nil {
+		return apperror.GRPCStatus(err)
+	}
+
+	// An empty range is still answered with one message describing it
+	for start := 0; start == 0 || start < len(result.bars); start += historyChunkSize {
+		end := min(start+historyChunkSize, len(result.bars))
+		chunk := &protov2.FetchPriceHistoryResponse{
+			Ticker:   query.ticker,
+			Interval: string(query.interval),
+			Source:   result.meta.Source,
+			Stale:    result.meta.Stale,
+			AsOf:     toTimestamp(result.meta.AsOf),
+			Bars:     make([]*protov2.Bar, 0, end-start),
+		}
+		if !query.interval.Intraday() {
+			chunk.Period = string(query.period)
+			chunk.Adjusted = query.adjusted
+		}
+		for _, p := range result.bars[start:end] {
+			bar, err := toBar(p, result.currency)
+			if err != nil {
+				return apperror.GRPCStatus(err)
+			}
+			chunk.Bars = append(chunk.Bars, bar)
+		}
+
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GRPCPriceFetcherServerV2) CreateAlert(ctx context.Context, req *protov2.CreateAlertRequest) (*protov2.Alert, error) {
+	threshold := req.GetThreshold()
+	if code := threshold.GetCurrencyCode(); code != "" {
+		if currency := tickerCurrency(req.Ticker); currency != "" && code != currency {
+			return nil, apperror.GRPCStatus(apperror.InvalidArgument("threshold must be in %s, the currency %s trades in", currency, req.Ticker))
+		}
+	}
+
+	alert, err := s.alertSvc.CreateAlert(req.Ticker, fromProtoCondition(req.Condition), fromMoney(threshold), req.WebhookUrl)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	return toProtoAlert(alert), nil
+}
+
+func (s *GRPCPriceFetcherServerV2) GetAlert(ctx context.Context, req *protov2.GetAlertRequest) (*protov2.Alert, error) {
+	alert, err := s.alertSvc.GetAlert(req.Id)
+	if err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	return toProtoAlert(alert), nil
+}
+
+func (s *GRPCPriceFetcherServerV2) ListAlerts(ctx context.Context, req *protov2.ListAlertsRequest) (*protov2.ListAlertsResponse, error) {
+	alerts := s.alertSvc.ListAlerts()
+
+	// Oldest first, so repeated calls list alerts in the same order
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].CreatedAt.Equal(alerts[j].CreatedAt) {
+			return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+		}
+		return alerts[i].ID < alerts[j].ID
+	})
+
+	resp := &protov2.ListAlertsResponse{Alerts: make([]*protov2.Alert, len(alerts))}
+	for i, alert := range alerts {
+		resp.Alerts[i] = toProtoAlert(alert)
+	}
+	return resp, nil
+}
+
+func (s *GRPCPriceFetcherServerV2) DeleteAlert(ctx context.Context, req *protov2.DeleteAlertRequest) (*protov2.DeleteAlertResponse, error) {
+	if err := s.alertSvc.DeleteAlert(req.Id); err != nil {
+		return nil, apperror.GRPCStatus(err)
+	}
+	return &protov2.DeleteAlertResponse{}, nil
+}
+
+// toBar converts a bar to its v2 form. Intraday bars also carry their
+// start time.
+func toBar(p types.HistoricalPricePoint, currency string) (*protov2.Bar, error) {
+	bar := &protov2.Bar{
+		Date:             p.Date,
+		Open:             toMoney(p.Open, currency),
+		High:             toMoney(p.High, currency),
+		Low:              toMoney(p.Low, currency),
+		Close:            toMoney(p.Close, currency),
+		Volume:           p.Volume,
+		SplitCoefficient: p.SplitCoefficient,
+	}
+	if p.AdjustedClose != 0 {
+		bar.AdjustedClose = toMoney(p.AdjustedClose, currency)
+	}
+	if p.DividendAmount != 0 {
+		bar.DividendAmount = toMoney(p.DividendAmount, currency)
+	}
+
+	if len(p.Date) > len(history.DateLayout) {
+		start, err := time.Parse(time.RFC3339, p.Date)
+		if err != nil {
+			return nil, apperror.New(apperror.ErrInternal, "invalid bar time %q", p.Date)
+		}
+		bar.Date = p.Date[:len(history.DateLayout)]
+		bar.Time = timestamppb.New(start)
+	}
+	return bar, nil
+}
+
+// toProtoAlert converts an alert to its v2 form
+func toProtoAlert(alert *service.Alert) *protov2.Alert {
+	resp := &protov2.Alert{
+		Id:         alert.ID,
+		Ticker:     alert.Ticker,
+		Condition:  toProtoCondition(alert.Condition),
+		Threshold:  toMoney(alert.Threshold, tickerCurrency(alert.Ticker)),
+		WebhookUrl: alert.WebhookURL,
+		Active:     alert.Active,
+		CreatedAt:  timestamppb.New(alert.CreatedAt),
+	}
+	if alert.TriggeredAt != nil {
+		resp.TriggeredAt = timestamppb.New(*alert.TriggeredAt)
+	}
+	return resp
+}
+
+func toProtoCondition(condition service.AlertCondition) protov2.AlertCondition {
+	switch condition {
+	case service.ConditionAbove:
+		return protov2.AlertCondition_ALERT_CONDITION_ABOVE
+	case service.ConditionBelow:
+		return protov2.AlertCondition_ALERT_CONDITION_BELOW
+	default:
+		return protov2.AlertCondition_ALERT_CONDITION_UNSPECIFIED
+	}
+}
+
+// fromProtoCondition converts a v2 condition. Unspecified conditions are
+// left for the alert service to reject.
+func fromProtoCondition(condition protov2.AlertCondition) service.AlertCondition {
+	switch condition {
+	case protov2.AlertCondition_ALERT_CONDITION_ABOVE:
+		return service.ConditionAbove
+	case protov2.AlertCondition_ALERT_CONDITION_BELOW:
+		return service.ConditionBelow
+	default:
+		return ""
+	}
+}
+
+// fromMoney converts a money amount, or nil for zero
+func fromMoney(m *protov2.Money) float64 {
+	return float64(m.GetUnits()) + float64(m.GetNanos())/1e9
+}
+
+// toMoney converts an amount to whole units and nanos, rounded to the
+// nearest nano. Providers parse prices from decimal strings, so rounding
+// recovers the digits they were quoted with.
+func toMoney(amount float64, currency string) *protov2.Money {
+	units, fraction := math.Modf(amount)
+	nanos := math.Round(fraction * 1e9)
+	if math.Abs(nanos) >= 1e9 {
+		units += math.Copysign(1, nanos)
+		nanos = 0
+	}
+	return &protov2.Money{
+		CurrencyCode: currency,
+		Units:        int64(units),
+		Nanos:        int32(nanos),
+	}
+}
+
+// toTimestamp converts the time a value was fetched, or nil if unknown
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,340 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+	protov2 "github.com/aliexe/ms-priceFetcher/proto/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestToMoney(t *testing.T) {
+	tests := []struct {
+		amount float64
+		units  int64
+		nanos  int32
+	}{
+		{amount: 2800.13, units: 2800, nanos: 130000000},
+		{amount: 190.2500, units: 190, nanos: 250000000},
+		{amount: 0.0535, units: 0, nanos: 53500000},
+		{amount: -1.35, units: -1, nanos: -350000000},
+		{amount: 43000, units: 43000, nanos: 0},
+		{amount: 9.9999999999, units: 10, nanos: 0},
+	}
+
+	for _, tt := range tests {
+		got := toMoney(tt.amount, "USD")
+		if got.Units != tt.units || got.Nanos != tt.nanos || got.CurrencyCode != "USD" {
+			t.Errorf("toMoney(%v) = %d units %d nanos %s, want %d units %d nanos USD",
+				tt.amount, got.Units, got.Nanos, got.CurrencyCode, tt.units, tt.nanos)
+		}
+	}
+}
+
+// historyStream collects the messages of a FetchPriceHistory call
+type historyStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*protov2.FetchPriceHistoryResponse
+}
+
+func (s *historyStream) Context() context.Context { return s.ctx }
+
+func (s *historyStream) Send(m *protov2.FetchPriceHistoryResponse) error {
+	s.messages = append(s.messages, m)
+	return nil
+}
+
+func newTestServerV2() *GRPCPriceFetcherServerV2 {
+	svc := service.NewPriceService()
+	hub := pricehub.NewHub(svc, pricehub.Options{})
+	return NewGRPCPriceFetcherServerV2(svc, service.NewAlertService(svc), svc.(service.RateService), hub)
+}
+
+func TestGRPCServerV2_FetchPrices(t *testing.T) {
+	s := newTestServerV2()
+
+	resp, err := s.FetchPrices(context.Background(), &protov2.FetchPricesRequest{
+		Tickers:  []string{"googl", "AAPL", "GOOGL"},
+		Currency: "eur",
+	})
+	if err != nil {
+		t.Fatalf("FetchPrices() error = %v", err)
+	}
+
+	if len(resp.Prices) != 2 || resp.Prices[0].Ticker != "GOOGL" || resp.Prices[1].Ticker != "AAPL" {
+		t.Fatalf("FetchPrices() prices = %v, want GOOGL then AAPL", resp.Prices)
+	}
+	if got := resp.Prices[0].Price; got.CurrencyCode != "EUR" || got.Units != 2576 {
+		t.Errorf("GOOGL price = %v, want 2576 EUR", got)
+	}
+
+	if _, err := s.FetchPrices(context.Background(), &protov2.FetchPricesRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("FetchPrices(no tickers) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGRPCServerV2_FetchPriceHistory(t *testing.T) {
+	s := newTestServerV2()
+
+	stream := &historyStream{ctx: context.Background()}
+	err := s.FetchPriceHistory(&protov2.FetchPriceHistoryRequest{Ticker: "AAPL", Interval: "1min"}, stream)
+	if err != nil {
+		t.Fatalf("FetchPriceHistory() error = %v", err)
+	}
+
+	if len(stream.messages) < 2 {
+		t.Fatalf("got %d messages, want the range split into chunks", len(stream.messages))
+	}
+	for _, m := range stream.messages {
+		if m.Ticker != "AAPL" || m.Interval != "1min" || len(m.Bars) > historyChunkSize {
+			t.Errorf("chunk = %s %s with %d bars", m.Ticker, m.Interval, len(m.Bars))
+		}
+	}
+	first := stream.messages[0].Bars[0]
+	if first.Date != "2024-01-01" || first.Time == nil || first.Close.GetCurrencyCode() != "USD" {
+		t.Errorf("first bar = %v", first)
+	}
+
+	err = s.FetchPriceHistory(&protov2.FetchPriceHistoryRequest{Ticker: "AAPL", Interval: "5min", Adjusted: true}, stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("FetchPriceHistory(adjusted intraday) error = %v, want InvalidArgument", err)
+	}
+}
+
+// priceStream collects the messages of a StreamPrices call, ending the
+// call once it has want of them
+type priceStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	cancel   context.CancelFunc
+	want     int
+	messages []*protov2.StreamPricesResponse
+}
+
+func (s *priceStream) Context() context.Context { return s.ctx }
+
+func (s *priceStream) Send(m *protov2.StreamPricesResponse) error {
+	s.messages = append(s.messages, m)
+	if len(s.messages) == s.want {
+		s.cancel()
+	}
+	return nil
+}
+
+func TestGRPCServerV2_StreamPricesSnapshot(t *testing.T) {
+	s := newTestServerV2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	stream := &priceStream{ctx: ctx, cancel: cancel, want: 2}
+
+	// An hour-long interval would send nothing before the timeout without
+	// the snapshot
+	err := s.StreamPrices(&protov2.StreamPricesRequest{
+		Tickers:               []string{"AAPL", "googl", "GOOGL", "ZZZZ"},
+		IntervalSeconds:       60,
+		TickerIntervalSeconds: map[string]int32{"GOOGL": 3600},
+		SnapshotFirst:         true,
+	}, stream)
+	if err != nil {
+		t.Fatalf("StreamPrices() error = %v", err)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Fatal("timed out waiting for the snapshot")
+	}
+
+	got := make([]string, len(stream.messages))
+	for i, m := range stream.messages {
+		got[i] = m.Ticker
+		if m.Price.GetCurrencyCode() != "USD" || m.PreviousPrice != nil {
+			t.Errorf("snapshot = %v", m)
+		}
+	}
+	if len(got) != 2 || got[0] != "AAPL" || got[1] != "GOOGL" {
+		t.Errorf("snapshot tickers = %v, want [AAPL GOOGL]", got)
+	}
+
+	tooMany := make([]string, maxBatchTickers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("T%d", i)
+	}
+	for _, req := range []*protov2.StreamPricesRequest{
+		{},
+		{Tickers: tooMany},
+		{Tickers: []string{"AAPL"}, IntervalSeconds: -1},
+		{Tickers: []string{"AAPL"}, TickerIntervalSeconds: map[string]int32{"MSFT": 10}},
+	} {
+		err := s.StreamPrices(req, &priceStream{ctx: context.Background()})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("StreamPrices(%v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestGRPCServerV2_Alerts(t *testing.T) {
+	s := newTestServerV2()
+	ctx := context.Background()
+
+	created, err := s.CreateAlert(ctx, &protov2.CreateAlertRequest{
+		Ticker:     "aapl",
+		Condition:  protov2.AlertCondition_ALERT_CONDITION_ABOVE,
+		Threshold:  &protov2.Money{CurrencyCode: "USD", Units: 200, Nanos: 500000000},
+		WebhookUrl: "http://localhost/hook",
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if created.Ticker != "AAPL" || !created.Active || created.Threshold.Units != 200 || created.Threshold.Nanos != 500000000 {
+		t.Errorf("CreateAlert() = %v", created)
+	}
+
+	got, err := s.GetAlert(ctx, &protov2.GetAlertRequest{Id: created.Id})
+	if err != nil || got.Condition != protov2.AlertCondition_ALERT_CONDITION_ABOVE {
+		t.Errorf("GetAlert() = %v, %v", got, err)
+	}
+	list, err := s.ListAlerts(ctx, &protov2.ListAlertsRequest{})
+	if err != nil || len(list.Alerts) != 1 {
+		t.Errorf("ListAlerts() = %v, %v; want 1 alert", list, err)
+	}
+
+	if _, err := s.DeleteAlert(ctx, &protov2.DeleteAlertRequest{Id: created.Id}); err != nil {
+		t.Fatalf("DeleteAlert() error = %v", err)
+	}
+	if _, err := s.GetAlert(ctx, &protov2.GetAlertRequest{Id: created.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetAlert(deleted) error = %v, want NotFound", err)
+	}
+
+	invalid := []*protov2.CreateAlertRequest{
+		{Ticker: "AAPL", Threshold: &protov2.Money{CurrencyCode: "USD", Units: 200}, WebhookUrl: "http://localhost/hook"},
+		{Ticker: "AAPL", Condition: protov2.AlertCondition_ALERT_CONDITION_BELOW, WebhookUrl: "http://localhost/hook"},
+		{Ticker: "AAPL", Condition: protov2.AlertCondition_ALERT_CONDITION_BELOW, Threshold: &protov2.Money{CurrencyCode: "EUR", Units: 200}, WebhookUrl: "http://localhost/hook"},
+	}
+	for _, req := range invalid {
+		if _, err := s.CreateAlert(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateAlert(%v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestGRPCServerV2_Subscribe(t *testing.T) {
+	interval, heartbeat := subscribeInterval, heartbeatInterval
+	subscribeInterval, heartbeatInterval = 10*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { subscribeInterval, heartbeatInterval = interval, heartbeat })
+
+	ln := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	protov2.RegisterPriceFetcherServer(server, newTestServerV2())
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := protov2.NewPriceFetcherClient(conn).Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	err = stream.Send(&protov2.SubscribeRequest{
+		RequestId: "1",
+		Action:    &protov2.SubscribeRequest_Subscribe{Subscribe: &protov2.TickerList{Tickers: []string{"aapl", "ZZZZ", ""}}},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	ack := recvAck(t, stream)
+	if ack.RequestId != "1" || len(ack.Tickers) != 2 || ack.Tickers[0] != "AAPL" || ack.Tickers[1] != "ZZZZ" {
+		t.Errorf("ack = %v, want AAPL and ZZZZ subscribed", ack)
+	}
+	if len(ack.Errors) != 1 || ack.Errors[0].Code != "InvalidArgument" {
+		t.Errorf("ack errors = %v, want the empty ticker rejected", ack.Errors)
+	}
+
+	// Prices keep coming; the unknown ticker's error is sent once
+	var prices, errs int
+	for prices < 3 {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		switch m := resp.Message.(type) {
+		case *protov2.SubscribeResponse_Price:
+			if m.Price.Ticker != "AAPL" || m.Price.Price.GetUnits() != 150 {
+				t.Errorf("price = %v, want AAPL at 150", m.Price)
+			}
+			prices++
+		case *protov2.SubscribeResponse_Error:
+			if m.Error.Ticker != "ZZZZ" || m.Error.Code != "NotFound" {
+				t.Errorf("error = %v, want ZZZZ not found", m.Error)
+			}
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors for ZZZZ, want 1", errs)
+	}
+
+	err = stream.Send(&protov2.SubscribeRequest{
+		RequestId: "2",
+		Action:    &protov2.SubscribeRequest_Unsubscribe{Unsubscribe: &protov2.TickerList{Tickers: []string{"AAPL", "ZZZZ"}}},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if ack := recvAck(t, stream); ack.RequestId != "2" || len(ack.Tickers) != 0 {
+		t.Errorf("ack = %v, want nothing subscribed", ack)
+	}
+
+	// With nothing subscribed the stream is kept alive by heartbeats
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if resp.GetHeartbeat() == nil {
+		t.Errorf("got %v, want a heartbeat", resp)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv() error = %v, want EOF", err)
+		}
+	}
+}
+
+// recvAck receives messages until the next ack, skipping prices sent before it
+func recvAck(t *testing.T, stream protov2.PriceFetcher_SubscribeClient) *protov2.SubscriptionAck {
+	t.Helper()
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		if ack := resp.GetAck(); ack != nil {
+			return ack
+		}
+	}
+}
@@ -3,12 +3,20 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
 	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
 	"github.com/aliexe/ms-priceFetcher/pkg/types"
 	"github.com/google/uuid"
 )
@@ -17,34 +25,56 @@ type APIFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request) e
 type JSONAPIServer struct {
 	svc        service.PriceService
 	alertSvc   *service.AlertService
+	ticks      *ticks.Store
+	rates      service.RateService
+	hub        *pricehub.Hub
 	listenAddr string
 	server     *http.Server
+
+	// shutdown is closed by Shutdown to end open streams, which
+	// http.Server.Shutdown does not wait for or close
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
-func NewJSONAPIServer(listenAddr string, svc service.PriceService, alertSvc *service.AlertService) *JSONAPIServer {
+func NewJSONAPIServer(listenAddr string, svc service.PriceService, alertSvc *service.AlertService, tickStore *ticks.Store, rates service.RateService, hub *pricehub.Hub) *JSONAPIServer {
 	return &JSONAPIServer{
 		svc:        svc,
 		alertSvc:   alertSvc,
+		ticks:      tickStore,
+		rates:      rates,
+		hub:        hub,
 		listenAddr: listenAddr,
+		shutdown:   make(chan struct{}),
 	}
 }
 
 func (s *JSONAPIServer) Run() error {
+	s.server = &http.Server{
+		Addr:    s.listenAddr,
+		Handler: s.Handler(),
+	}
+
+	fmt.Println("Server started on", s.listenAddr)
+	return s.server.ListenAndServe()
+}
+
+// Handler returns the routes of the API
+func (s *JSONAPIServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/price", makeHTTPHandler(s.handleFetchPrice))
 	mux.HandleFunc("/prices", makeHTTPHandler(s.handleFetchPrices))
+	mux.HandleFunc("/quote", makeHTTPHandler(s.handleFetchQuote))
+	mux.HandleFunc("/symbols/search", makeHTTPHandler(s.handleSearchSymbols))
+	mux.HandleFunc("/symbols/", makeHTTPHandler(s.handleFetchSymbol))
 	mux.HandleFunc("/price/history", makeHTTPHandler(s.handleFetchPriceHistory))
+	mux.HandleFunc("/price/ticks", makeHTTPHandler(s.handleFetchTicks))
 	mux.HandleFunc("/alerts", s.handleAlerts)
 	mux.HandleFunc("/alerts/", s.handleAlertByID)
+	mux.HandleFunc("/ws/prices", s.handleWebSocketPrices)
+	mux.HandleFunc("/stream/prices", s.handleStreamPrices)
 	mux.HandleFunc("/health", s.handleHealth)
-
-	s.server = &http.Server{
-		Addr:    s.listenAddr,
-		Handler: mux,
-	}
-
-	fmt.Println("Server started on", s.listenAddr)
-	return s.server.ListenAndServe()
+	return mux
 }
 
 func (s *JSONAPIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -54,6 +84,7 @@ func (s *JSONAPIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *JSONAPIServer) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
 	}
@@ -66,99 +97,165 @@ func makeHTTPHandler(apiFn APIFunc) http.HandlerFunc {
 		reqID := uuid.New().ID()
 		ctx = context.WithValue(ctx, "requestID", reqID)
 		if err := apiFn(ctx, w, r); err != nil {
-			statusCode := http.StatusInternalServerError
-			if isClientError(err) {
-				statusCode = http.StatusBadRequest
+			var quotaErr *service.QuotaExhaustedError
+			if errors.As(err, &quotaErr) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
 			}
-			http.Error(w, err.Error(), statusCode)
+			http.Error(w, err.Error(), apperror.HTTPStatus(err))
 		}
 	}
 }
 
-func isClientError(err error) bool {
-	if err == nil {
-		return false
+func (s *JSONAPIServer) handleFetchPrice(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ticker, err := symbol.Normalize(r.URL.Query().Get("ticker"))
+	if err != nil {
+		return err
 	}
-	errMsg := err.Error()
-	clientErrorMessages := []string{
-		"ticker is required",
-		"invalid ticker",
-		"ticker not found",
+	currency, err := parseCurrency(r.URL.Query().Get("currency"), s.rates)
+	if err != nil {
+		return err
 	}
-	for _, msg := range clientErrorMessages {
-		if contains(errMsg, msg) {
-			return true
+
+	// Rates are fetched outside the response metadata, which describes
+	// the price
+	rateCtx := ctx
+	ctx, meta := service.WithResponseMeta(ctx)
+	price, err := s.svc.FetchPrice(ctx, ticker)
+	if err != nil {
+		return err
+	}
+
+	priceCurrency := tickerCurrency(ticker)
+	if currency != "" {
+		rate, err := service.ExchangeRate(rateCtx, s.rates, priceCurrency, currency)
+		if err != nil {
+			return err
 		}
+		price, priceCurrency = price*rate, currency
 	}
-	return false
-}
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsSubstring(s, substr)))
+	tickerMeta := meta.Ticker(ticker)
+	priceResponse := types.PriceResponse{
+		Ticker:   ticker,
+		Price:    price,
+		Currency: priceCurrency,
+		Source:   tickerMeta.Source,
+		Stale:    tickerMeta.Stale,
+		AsOf:     formatAsOf(tickerMeta.AsOf),
+	}
+	return writeJSON(w, http.StatusOK, priceResponse)
 }
 
-func containsSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+func (s *JSONAPIServer) handleFetchQuote(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ticker, err := symbol.Normalize(r.URL.Query().Get("ticker"))
+	if err != nil {
+		return err
+	}
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	quote, err := s.svc.FetchQuote(ctx, ticker)
+	if err != nil {
+		return err
+	}
+	tickerMeta := meta.Ticker(ticker)
+	quoteResponse := types.QuoteResponse{
+		Quote:    quote,
+		Currency: tickerCurrency(ticker),
+		Source:   tickerMeta.Source,
+		Stale:    tickerMeta.Stale,
+		AsOf:     formatAsOf(tickerMeta.AsOf),
 	}
-	return false
+	return writeJSON(w, http.StatusOK, quoteResponse)
 }
 
-func (s *JSONAPIServer) handleFetchPrice(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	ticker := r.URL.Query().Get("ticker")
-	if ticker == "" {
-		return fmt.Errorf("ticker is required")
+// maxSearchQueryLength bounds /symbols/search queries
+const maxSearchQueryLength = 64
+
+// symbolCacheMaxAge lets clients and proxies keep symbol metadata for a day
+const symbolCacheMaxAge = 24 * time.Hour
+
+func (s *JSONAPIServer) handleSearchSymbols(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		return apperror.InvalidArgument("q is required")
+	}
+	if len(query) > maxSearchQueryLength {
+		return apperror.InvalidArgument("q must be at most %d characters", maxSearchQueryLength)
 	}
 
-	// Validate ticker format
-	if !isValidTicker(ticker) {
-		return fmt.Errorf("invalid ticker format: must be 1-10 alphanumeric characters")
+	ctx, meta := service.WithResponseMeta(ctx)
+	matches, err := s.svc.SearchSymbols(ctx, query)
+	if err != nil {
+		return err
 	}
+	response := types.SymbolSearchResponse{
+		Query:   query,
+		Source:  meta.Source(query),
+		Results: matches,
+	}
+	return writeJSON(w, http.StatusOK, response)
+}
 
-	price, err := s.svc.FetchPrice(ctx, ticker)
+func (s *JSONAPIServer) handleFetchSymbol(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ticker, err := symbol.Normalize(strings.TrimPrefix(r.URL.Path, "/symbols/"))
 	if err != nil {
 		return err
 	}
-	priceResponse := types.PriceResponse{
-		Ticker: ticker,
-		Price:  price,
+
+	ctx, meta := service.WithResponseMeta(ctx)
+	info, err := s.svc.FetchSymbol(ctx, ticker)
+	if err != nil {
+		return err
 	}
-	return writeJSON(w, http.StatusOK, priceResponse)
+	response := types.SymbolResponse{
+		SymbolInfo: info,
+		Source:     meta.Source(ticker),
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(symbolCacheMaxAge.Seconds())))
+	return writeJSON(w, http.StatusOK, response)
 }
 
 func (s *JSONAPIServer) handleFetchPrices(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	tickersParam := r.URL.Query().Get("tickers")
 	if tickersParam == "" {
-		return fmt.Errorf("tickers parameter is required")
+		return apperror.InvalidArgument("tickers parameter is required")
 	}
 
-	// Parse comma-separated tickers
-	tickers := parseTickers(tickersParam)
-	if len(tickers) == 0 {
-		return fmt.Errorf("at least one valid ticker is required")
-	}
-
-	// Validate all tickers
-	for _, ticker := range tickers {
-		if !isValidTicker(ticker) {
-			return fmt.Errorf("invalid ticker format: %s must be 1-10 alphanumeric characters", ticker)
-		}
+	// Parse, validate and normalize comma-separated tickers
+	tickers, err := normalizeTickers(parseTickers(tickersParam))
+	if err != nil {
+		return err
 	}
-
-	// Limit to 50 tickers per request
-	if len(tickers) > 50 {
-		return fmt.Errorf("maximum 50 tickers per request")
+	currency, err := parseCurrency(r.URL.Query().Get("currency"), s.rates)
+	if err != nil {
+		return err
 	}
 
+	rateCtx := ctx
+	ctx, meta := service.WithResponseMeta(ctx)
 	prices, err := s.svc.FetchPrices(ctx, tickers)
 	if err != nil {
 		return err
 	}
+	currencies, conversionErrors, err := convertPrices(rateCtx, s.rates, prices, currency)
+	if err != nil {
+		return err
+	}
+
+	asOf := make(map[string]string, len(prices))
+	for ticker := range prices {
+		if formatted := formatAsOf(meta.Ticker(ticker).AsOf); formatted != "" {
+			asOf[ticker] = formatted
+		}
+	}
 
 	batchResponse := types.BatchPriceResponse{
-		Prices: prices,
+		Prices:     prices,
+		Currencies: currencies,
+		Sources:    meta.Sources(),
+		Stale:      meta.StaleTickers(),
+		AsOf:       asOf,
+		Errors:     conversionErrors,
 	}
 	return writeJSON(w, http.StatusOK, batchResponse)
 }
@@ -175,39 +272,107 @@ func parseTickers(tickersParam string) []string {
 }
 
 func (s *JSONAPIServer) handleFetchPriceHistory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	ticker := r.URL.Query().Get("ticker")
-	if ticker == "" {
-		return fmt.Errorf("ticker is required")
+	query := r.URL.Query()
+	adjusted := false
+	if param := query.Get("adjusted"); param != "" {
+		var err error
+		adjusted, err = strconv.ParseBool(param)
+		if err != nil {
+			return apperror.InvalidArgument("invalid adjusted value %q: use true or false", param)
+		}
 	}
 
-	// Validate ticker format
-	if !isValidTicker(ticker) {
-		return fmt.Errorf("invalid ticker format: must be 1-10 alphanumeric characters")
+	req, err := parseHistoryRequest(query.Get("ticker"), query.Get("interval"), query.Get("period"), adjusted,
+		query.Get("from"), query.Get("to"), query.Get("currency"), s.rates)
+	if err != nil {
+		return err
 	}
 
-	fromDate := r.URL.Query().Get("from")
-	toDate := r.URL.Query().Get("to")
+	result, err := fetchHistory(ctx, s.svc, s.rates, req)
+	if err != nil {
+		return err
+	}
 
-	// Validate date format if provided
-	if fromDate != "" && !isValidDate(fromDate) {
-		return fmt.Errorf("invalid from date format: use YYYY-MM-DD")
+	response := types.HistoricalPriceResponse{
+		Ticker:   req.ticker,
+		Interval: string(req.interval),
+		Currency: result.currency,
+		Source:   result.meta.Source,
+		Stale:    result.meta.Stale,
+		AsOf:     formatAsOf(result.meta.AsOf),
+		Data:     result.bars,
+	}
+	if !req.interval.Intraday() {
+		response.Period = string(req.period)
+		response.Adjusted = req.adjusted
 	}
-	if toDate != "" && !isValidDate(toDate) {
-		return fmt.Errorf("invalid to date format: use YYYY-MM-DD")
+	return writeJSON(w, http.StatusOK, response)
+}
+
+// defaultTickWindow is how far back /price/ticks looks when from is omitted
+const defaultTickWindow = 24 * time.Hour
+
+func (s *JSONAPIServer) handleFetchTicks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if s.ticks == nil {
+		return apperror.NotFound("tick recording is disabled")
 	}
 
-	history, err := s.svc.FetchPriceHistory(ctx, ticker, fromDate, toDate)
+	ticker, err := symbol.Normalize(r.URL.Query().Get("ticker"))
 	if err != nil {
 		return err
 	}
 
-	response := types.HistoricalPriceResponse{
+	to := time.Now()
+	if param := r.URL.Query().Get("to"); param != "" {
+		parsed, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return apperror.InvalidArgument("invalid to time format: use RFC3339")
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultTickWindow)
+	if param := r.URL.Query().Get("from"); param != "" {
+		parsed, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return apperror.InvalidArgument("invalid from time format: use RFC3339")
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return apperror.InvalidArgument("from must not be after to")
+	}
+
+	recorded, err := s.ticks.Query(ticker, from, to)
+	if err != nil {
+		return apperror.Wrap(apperror.ErrInternal, err, "failed to read ticks")
+	}
+
+	points := make([]types.TickPoint, len(recorded))
+	for i, tick := range recorded {
+		points[i] = types.TickPoint{
+			Time:   tick.Time.UTC().Format(time.RFC3339Nano),
+			Price:  tick.Price,
+			Source: tick.Source,
+		}
+	}
+
+	response := types.TickResponse{
 		Ticker: ticker,
-		Data:   history,
+		From:   from.UTC().Format(time.RFC3339),
+		To:     to.UTC().Format(time.RFC3339),
+		Ticks:  points,
 	}
 	return writeJSON(w, http.StatusOK, response)
 }
 
+// formatAsOf renders the time a value was fetched, or "" if unknown
+func formatAsOf(asOf time.Time) string {
+	if asOf.IsZero() {
+		return ""
+	}
+	return asOf.UTC().Format(time.RFC3339)
+}
+
 func isValidDate(date string) bool {
 	if len(date) != 10 {
 		return false
@@ -236,30 +401,10 @@ func (s *JSONAPIServer) handleCreateAlert(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Validate request
-	if req.Ticker == "" {
-		http.Error(w, "ticker is required", http.StatusBadRequest)
-		return
-	}
-
-	if !isValidTicker(req.Ticker) {
-		http.Error(w, "invalid ticker format", http.StatusBadRequest)
-		return
-	}
-
-	if req.Condition != string(service.ConditionAbove) && req.Condition != string(service.ConditionBelow) {
-		http.Error(w, "condition must be 'above' or 'below'", http.StatusBadRequest)
-		return
-	}
-
-	if req.Threshold <= 0 {
-		http.Error(w, "threshold must be positive", http.StatusBadRequest)
-		return
-	}
-
+	// The alert service validates the request and normalizes the ticker
 	alert, err := s.alertSvc.CreateAlert(req.Ticker, service.AlertCondition(req.Condition), req.Threshold, req.WebhookURL)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), apperror.HTTPStatus(err))
 		return
 	}
 
@@ -301,7 +446,7 @@ func (s *JSONAPIServer) handleAlertByID(w http.ResponseWriter, r *http.Request)
 	case "GET":
 		alert, err := s.alertSvc.GetAlert(alertID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			http.Error(w, err.Error(), apperror.HTTPStatus(err))
 			return
 		}
 
@@ -323,7 +468,7 @@ func (s *JSONAPIServer) handleAlertByID(w http.ResponseWriter, r *http.Request)
 
 	case "DELETE":
 		if err := s.alertSvc.DeleteAlert(alertID); err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			http.Error(w, err.Error(), apperror.HTTPStatus(err))
 			return
 		}
 
@@ -334,20 +479,6 @@ func (s *JSONAPIServer) handleAlertByID(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func isValidTicker(ticker string) bool {
-	if len(ticker) < 1 || len(ticker) > 10 {
-		return false
-	}
-
-	for _, r := range ticker {
-		if !((r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
-			return false
-		}
-	}
-
-	return true
-}
-
 func writeJSON(w http.ResponseWriter, s int, v any) error {
 	w.WriteHeader(s)
 	return json.NewEncoder(w).Encode(v)
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// maxBatchTickers bounds the tickers of one batch price request
+const maxBatchTickers = 50
+
+// normalizeTickers validates the tickers of a batch price request
+func normalizeTickers(raw []string) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, apperror.InvalidArgument("at least one valid ticker is required")
+	}
+	if len(raw) > maxBatchTickers {
+		return nil, apperror.InvalidArgument("maximum %d tickers per request", maxBatchTickers)
+	}
+
+	tickers := make([]string, len(raw))
+	for i, ticker := range raw {
+		normalized, err := symbol.Normalize(ticker)
+		if err != nil {
+			return nil, err
+		}
+		tickers[i] = normalized
+	}
+	return tickers, nil
+}
+
+// parseCurrency returns the currency prices are to be converted to, or ""
+// to leave them in the currency the ticker is priced in
+func parseCurrency(value string, rates service.RateService) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if rates == nil {
+		return "", apperror.NotFound("currency conversion is disabled")
+	}
+	return service.ParseCurrency(value)
+}
+
+// tickerCurrency returns the currency a normalized ticker is priced in
+func tickerCurrency(ticker string) string {
+	sym, err := symbol.Parse(ticker)
+	if err != nil {
+		return ""
+	}
+	return sym.Currency()
+}
+
+// convertPrices converts prices in place to currency, fetching each
+// currency's rate once, and returns the currency of each price. Prices that
+// cannot be converted are left out and reported in the returned messages;
+// if none can be, the error is returned instead.
+func convertPrices(ctx context.Context, rates service.RateService, prices map[string]float64, currency string) (map[string]string, []string, error) {
+	currencies := make(map[string]string, len(prices))
+	rateByCurrency := make(map[string]float64)
+	var conversionErrors []string
+	var conversionErr error
+
+	for ticker, price := range prices {
+		priceCurrency := tickerCurrency(ticker)
+		if currency != "" {
+			rate, ok := rateByCurrency[priceCurrency]
+			if !ok {
+				var err error
+				rate, err = service.ExchangeRate(ctx, rates, priceCurrency, currency)
+				if err != nil {
+					conversionErrors = append(conversionErrors, fmt.Sprintf("%s: %v", ticker, err))
+					conversionErr = err
+					delete(prices, ticker)
+					continue
+				}
+				rateByCurrency[priceCurrency] = rate
+			}
+			prices[ticker], priceCurrency = price*rate, currency
+		}
+		currencies[ticker] = priceCurrency
+	}
+
+	if len(prices) == 0 && conversionErr != nil {
+		return nil, nil, conversionErr
+	}
+	sort.Strings(conversionErrors)
+	return currencies, conversionErrors, nil
+}
+
+// historyRequest is a validated price history query
+type historyRequest struct {
+	ticker   string
+	interval service.Interval
+	period   service.Period
+	adjusted bool
+	fromDate string
+	toDate   string
+	currency string
+}
+
+// parseHistoryRequest validates a price history query. Interval and period
+// default to daily.
+func parseHistoryRequest(ticker, interval, period string, adjusted bool, fromDate, toDate, currency string, rates service.RateService) (historyRequest, error) {
+	var req historyRequest
+	var err error
+
+	if req.ticker, err = symbol.Normalize(ticker); err != nil {
+		return req, err
+	}
+	if req.interval, err = service.ParseInterval(interval); err != nil {
+		return req, err
+	}
+	if req.period, err = service.ParsePeriod(period); err != nil {
+		return req, err
+	}
+	if req.period != service.PeriodDaily && req.interval.Intraday() {
+		return req, apperror.InvalidArgument("period %s requires daily bars", req.period)
+	}
+	if adjusted && req.interval.Intraday() {
+		return req, apperror.InvalidArgument("adjusted bars require daily bars")
+	}
+	req.adjusted = adjusted
+	if req.currency, err = parseCurrency(currency, rates); err != nil {
+		return req, err
+	}
+
+	// Daily ranges take dates, intraday ranges also take RFC3339 times
+	if req.interval.Intraday() {
+		if err := service.ValidateHistoryBound(fromDate); err != nil {
+			return req, err
+		}
+		if err := service.ValidateHistoryBound(toDate); err != nil {
+			return req, err
+		}
+	} else {
+		if fromDate != "" && !isValidDate(fromDate) {
+			return req, apperror.InvalidArgument("invalid from date format: use YYYY-MM-DD")
+		}
+		if toDate != "" && !isValidDate(toDate) {
+			return req, apperror.InvalidArgument("invalid to date format: use YYYY-MM-DD")
+		}
+	}
+	req.fromDate, req.toDate = fromDate, toDate
+	return req, nil
+}
+
+// historyResult is a price history ready to be sent
+type historyResult struct {
+	bars     []types.HistoricalPricePoint
+	currency string
+	meta     service.TickerMeta
+}
+
+// fetchHistory fetches the bars of a history request, then adjusts,
+// converts and resamples them
+func fetchHistory(ctx context.Context, svc service.PriceService, rates service.RateService, req historyRequest) (historyResult, error) {
+	// Rates are fetched outside the response metadata, which describes
+	// the bars
+	rateCtx := ctx
+	ctx, meta := service.WithResponseMeta(ctx)
+	bars, err := svc.FetchPriceHistory(ctx, req.ticker, req.interval, req.fromDate, req.toDate)
+	if err != nil {
+		return historyResult{}, err
+	}
+
+	// Adjust and convert before resampling so each bar is scaled by its
+	// own day's factor and rate
+	if req.adjusted {
+		bars = service.AdjustBars(bars)
+	}
+	currency := tickerCurrency(req.ticker)
+	if req.currency != "" {
+		bars, err = service.ConvertBars(rateCtx, rates, bars, currency, req.currency)
+		if err != nil {
+			return historyResult{}, err
+		}
+		currency = req.currency
+	}
+	bars, err = service.ResampleBars(bars, req.period)
+	if err != nil {
+		return historyResult{}, err
+	}
+
+	return historyResult{bars: bars, currency: currency, meta: meta.Ticker(req.ticker)}, nil
+}
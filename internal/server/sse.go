@@ -0,0 +1,232 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// sseHeartbeatInterval is how long an event stream may be idle before a
+// comment is sent to keep proxies from closing it, and sseWriteWait how
+// long a write may block before the client is let go
+var (
+	sseHeartbeatInterval = 15 * time.Second
+	sseWriteWait         = 10 * time.Second
+)
+
+// maxReplayAge bounds how far back a resumed event stream is replayed
+const maxReplayAge = time.Hour
+
+// sseWatchBuffer is the number of recorded ticks an event stream may fall
+// behind by before it is closed, leaving the client to resume
+const sseWatchBuffer = 256
+
+// sseEvent is one event of a price event stream
+type sseEvent struct {
+	id   string
+	name string
+	data any
+}
+
+// handleStreamPrices streams prices as server-sent events. The tickers are
+// polled through the hub, and each price recorded in the tick store for
+// them is sent identified by its sequence number, so a client reconnecting
+// with Last-Event-ID is first sent the prices recorded since. Without a
+// tick store, polled prices are sent without IDs.
+func (s *JSONAPIServer) handleStreamPrices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	tickers, err := normalizeTickers(parseTickers(r.URL.Query().Get("tickers")))
+	if err != nil {
+		http.Error(w, err.Error(), apperror.HTTPStatus(err))
+		return
+	}
+
+	// Watch the store before polling starts so no recorded price is missed
+	var recorded <-chan ticks.Tick
+	if s.ticks != nil {
+		watcher := s.ticks.Watch(sseWatchBuffer)
+		defer watcher.Close()
+		recorded = watcher.Ticks()
+	}
+
+	session := newSubscriptionSession(s.hub, subscribeInterval)
+	defer session.close()
+	session.subscribe(tickers)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Ask reverse proxies not to buffer the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(write func(io.Writer) error) error {
+		rc.SetWriteDeadline(time.Now().Add(sseWriteWait))
+		if err := write(w); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	sendEvent := func(event sseEvent) error {
+		return send(func(w io.Writer) error { return writeSSEEvent(w, event) })
+	}
+	if err := send(func(w io.Writer) error { _, err := io.WriteString(w, ": connected\n\n"); return err }); err != nil {
+		return
+	}
+
+	// lastSeq is the sequence number of the last price sent, so prices
+	// already replayed are not sent twice
+	var lastSeq uint64
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" && s.ticks != nil {
+		events, err := replayTicks(s.ticks, session.set.list(), lastID, time.Now())
+		if err != nil {
+			sendEvent(sseEvent{name: "ticker_error", data: toJSONTickerError("", err)})
+			return
+		}
+		for _, tick := range events {
+			if err := sendEvent(tickEvent(tick)); err != nil {
+				return
+			}
+			lastSeq = tick.Seq
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var event sseEvent
+		select {
+		case tick, ok := <-recorded:
+			if !ok {
+				// Fell behind; the client resumes from the last event
+				return
+			}
+			if !session.set.contains(tick.Ticker) || tick.Seq <= lastSeq {
+				continue
+			}
+			event = tickEvent(tick)
+			lastSeq = tick.Seq
+
+		case u := <-session.sub.Updates():
+			if !session.deliver(u) {
+				continue
+			}
+			switch {
+			case u.Err != nil:
+				event = sseEvent{name: "ticker_error", data: toJSONTickerError(u.Ticker, u.Err)}
+			case recorded == nil:
+				event = priceEvent(u)
+			default:
+				// The price is sent once it is recorded
+				continue
+			}
+
+		case <-heartbeat.C:
+			if err := send(func(w io.Writer) error { _, err := io.WriteString(w, ": heartbeat\n\n"); return err }); err != nil {
+				return
+			}
+			continue
+
+		case <-session.sub.Dropped():
+			return
+
+		case <-s.shutdown:
+			return
+
+		case <-r.Context().Done():
+			return
+		}
+
+		if err := sendEvent(event); err != nil {
+			return
+		}
+		heartbeat.Reset(sseHeartbeatInterval)
+	}
+}
+
+// replayTicks returns the ticks recorded for tickers after the event
+// lastID, in the order they were recorded. Invalid IDs replay nothing, and
+// replay goes back at most maxReplayAge.
+func replayTicks(store *ticks.Store, tickers []string, lastID string, now time.Time) ([]ticks.Tick, error) {
+	after, err := strconv.ParseUint(lastID, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	recorded, err := store.QueryTickers(tickers, now.Add(-maxReplayAge), now)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.ErrInternal, err, "failed to read ticks")
+	}
+	replay := make([]ticks.Tick, 0, len(recorded))
+	for _, tick := range recorded {
+		if tick.Seq > after {
+			replay = append(replay, tick)
+		}
+	}
+	sort.Slice(replay, func(i, j int) bool { return replay[i].Seq < replay[j].Seq })
+	return replay, nil
+}
+
+// priceEvent is a polled price, sent when there is no tick store to
+// identify it by
+func priceEvent(u pricehub.Update) sseEvent {
+	return sseEvent{
+		name: "price",
+		data: types.StreamPrice{
+			Ticker:    u.Ticker,
+			Price:     u.Price,
+			Currency:  tickerCurrency(u.Ticker),
+			Timestamp: u.Time.Format(time.RFC3339),
+			Source:    u.Meta.Source,
+			Stale:     u.Meta.Stale,
+			AsOf:      formatAsOf(u.Meta.AsOf),
+		},
+	}
+}
+
+// tickEvent is a recorded price, live or replayed. Its as_of is the time
+// the price was observed.
+func tickEvent(tick ticks.Tick) sseEvent {
+	return sseEvent{
+		id:   strconv.FormatUint(tick.Seq, 10),
+		name: "price",
+		data: types.StreamPrice{
+			Ticker:    tick.Ticker,
+			Price:     tick.Price,
+			Currency:  tickerCurrency(tick.Ticker),
+			Timestamp: tick.Time.Format(time.RFC3339),
+			Source:    tick.Source,
+			Stale:     tick.Stale,
+			AsOf:      formatAsOf(tick.Time),
+		},
+	}
+}
+
+// writeSSEEvent writes an event in the text/event-stream format
+func writeSSEEvent(w io.Writer, event sseEvent) error {
+	data, err := json.Marshal(event.data)
+	if err != nil {
+		return err
+	}
+	if event.id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, data)
+	return err
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// testEvent is a parsed server-sent event, or a comment
+type testEvent struct {
+	id, name, data, comment string
+}
+
+func openEventStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) testEvent {
+	t.Helper()
+	var event testEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			event.comment = value
+		case "id":
+			event.id = value
+		case "event":
+			event.name = value
+		case "data":
+			event.data = value
+		}
+	}
+}
+
+// readPrice reads events until the next price
+func readPrice(t *testing.T, r *bufio.Reader) (testEvent, types.StreamPrice) {
+	t.Helper()
+	for {
+		event := readEvent(t, r)
+		if event.name != "price" {
+			continue
+		}
+		var price types.StreamPrice
+		if err := json.Unmarshal([]byte(event.data), &price); err != nil {
+			t.Fatalf("price data %q: %v", event.data, err)
+		}
+		return event, price
+	}
+}
+
+func newTestTickStore(t *testing.T) *ticks.Store {
+	t.Helper()
+	store, err := ticks.NewStore(t.TempDir(), ticks.Options{})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStreamPrices(t *testing.T) {
+	_, url := newTestJSONServer(t, newTestTickStore(t))
+	resp, r := openEventStream(t, url+"/stream/prices?tickers=aapl,ZZZZ", "")
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var prices, errs int
+	var lastSeq uint64
+	for prices < 3 {
+		event := readEvent(t, r)
+		switch event.name {
+		case "price":
+			var price types.StreamPrice
+			json.Unmarshal([]byte(event.data), &price)
+			seq, err := strconv.ParseUint(event.id, 10, 64)
+			if price.Ticker != "AAPL" || price.Price != 150 || price.AsOf == "" || err != nil || seq <= lastSeq {
+				t.Errorf("price event = %+v, want AAPL at 150 with an increasing id", event)
+			}
+			lastSeq = seq
+			prices++
+		case "ticker_error":
+			var tickerErr types.TickerError
+			json.Unmarshal([]byte(event.data), &tickerErr)
+			if tickerErr.Ticker != "ZZZZ" || tickerErr.Code != "NotFound" {
+				t.Errorf("error event = %+v, want ZZZZ not found", tickerErr)
+			}
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("got %d error events, want 1", errs)
+	}
+}
+
+func TestStreamPrices_WithoutTickStore(t *testing.T) {
+	_, url := newTestJSONServer(t, nil)
+	_, r := openEventStream(t, url+"/stream/prices?tickers=AAPL", "")
+
+	// Polled prices are sent, but cannot be resumed from
+	if event, price := readPrice(t, r); price.Ticker != "AAPL" || event.id != "" {
+		t.Errorf("price event = %+v, want AAPL without an id", event)
+	}
+}
+
+func TestStreamPrices_InvalidTickers(t *testing.T) {
+	_, url := newTestJSONServer(t, nil)
+
+	for _, query := range []string{"", "?tickers=", "?tickers=AAPL,%24%24"} {
+		resp, err := http.Get(url + "/stream/prices" + query)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET /stream/prices%s status = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestStreamPrices_Resume(t *testing.T) {
+	store := newTestTickStore(t)
+
+	start := time.Now().Add(-time.Minute)
+	recorded := []ticks.Tick{
+		{Ticker: "AAPL", Price: 140, Source: "mock", Time: start},
+		{Ticker: "MSFT", Price: 290, Source: "mock", Time: start.Add(2 * time.Second)},
+		// Recorded after MSFT but observed earlier, as a cached price is
+		{Ticker: "AAPL", Price: 145, Source: "mock", Time: start.Add(time.Second), Stale: true},
+		// Not subscribed to
+		{Ticker: "GOOGL", Price: 2700, Source: "mock", Time: start.Add(3 * time.Second)},
+	}
+	if err := store.Record(recorded...); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	_, url := newTestJSONServer(t, store)
+	_, r := openEventStream(t, url+"/stream/prices?tickers=AAPL,MSFT", strconv.FormatUint(recorded[0].Seq, 10))
+
+	// The prices recorded after the last event are replayed in the order
+	// they were recorded, then live ones follow
+	event, price := readPrice(t, r)
+	if price.Ticker != "MSFT" || price.Price != 290 || event.id != strconv.FormatUint(recorded[1].Seq, 10) {
+		t.Errorf("first replayed event = %+v, want MSFT at 290", event)
+	}
+	event, price = readPrice(t, r)
+	if price.Ticker != "AAPL" || price.Price != 145 || event.id != strconv.FormatUint(recorded[2].Seq, 10) {
+		t.Errorf("second replayed event = %+v, want AAPL at 145", event)
+	}
+	if !price.Stale || price.AsOf != start.Add(time.Second).UTC().Format(time.RFC3339) {
+		t.Errorf("replayed price = %+v, want it stale as of when it was observed", price)
+	}
+	event, price = readPrice(t, r)
+	if seq, _ := strconv.ParseUint(event.id, 10, 64); (price.Price != 150 && price.Price != 300) || seq <= recorded[3].Seq {
+		t.Errorf("live price = %+v, want a mock price after the recorded ones", event)
+	}
+}
+
+func TestStreamPrices_Heartbeat(t *testing.T) {
+	heartbeat := sseHeartbeatInterval
+	sseHeartbeatInterval = 10 * time.Millisecond
+	t.Cleanup(func() { sseHeartbeatInterval = heartbeat })
+
+	_, url := newTestJSONServer(t, nil)
+	subscribeInterval = time.Hour
+	_, r := openEventStream(t, url+"/stream/prices?tickers=AAPL", "")
+
+	if event := readEvent(t, r); event.comment != "connected" {
+		t.Errorf("first event = %+v, want the connected comment", event)
+	}
+	if event := readEvent(t, r); event.comment != "heartbeat" || event.name != "" {
+		t.Errorf("idle stream sent %+v, want a heartbeat comment", event)
+	}
+}
+
+func TestStreamPrices_Shutdown(t *testing.T) {
+	s, url := newTestJSONServer(t, nil)
+	_, r := openEventStream(t, url+"/stream/prices?tickers=AAPL", "")
+	readPrice(t, r)
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	// The stream ends once the events already sent are read
+	for {
+		_, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading event stream: %v, want it closed", err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+)
+
+// Stream modes, choosing which polled prices a stream sends
+const (
+	modeEveryTick = "every_tick"
+	modeOnChange  = "on_change"
+	modeMinDelta  = "min_delta"
+)
+
+// defaultStreamInterval is how often a price stream polls tickers when no
+// interval is requested
+const defaultStreamInterval = 5 * time.Second
+
+// defaultKeepalive is how long on_change and min_delta streams may be idle
+// before a keepalive is sent
+const defaultKeepalive = 30 * time.Second
+
+// streamOptions is a validated stream mode
+type streamOptions struct {
+	mode            string
+	minDeltaAmount  float64
+	minDeltaPercent float64
+	// keepalive is zero when no keepalives are sent
+	keepalive time.Duration
+	// snapshotFirst sends each ticker's current price when the stream
+	// starts
+	snapshotFirst bool
+}
+
+// streamIntervals is how often each ticker of a price stream is polled
+type streamIntervals struct {
+	interval  time.Duration
+	perTicker map[string]time.Duration
+}
+
+// of returns the interval a ticker is polled at
+func (i streamIntervals) of(ticker string) time.Duration {
+	if interval, ok := i.perTicker[ticker]; ok {
+		return interval
+	}
+	return i.interval
+}
+
+// parseStreamIntervals validates the intervals of a price stream against
+// the hub's bounds. The interval defaults to defaultStreamInterval, clamped
+// to the bounds since the client did not ask for it; per ticker intervals
+// must name tickers of the stream.
+func parseStreamIntervals(hub *pricehub.Hub, tickers []string, intervalSeconds int32, perTicker map[string]int32) (streamIntervals, error) {
+	intervals := streamIntervals{interval: hub.ClampInterval(defaultStreamInterval)}
+	switch {
+	case intervalSeconds < 0:
+		return intervals, apperror.InvalidArgument("interval_seconds must not be negative")
+	case intervalSeconds > 0:
+		intervals.interval = time.Duration(intervalSeconds) * time.Second
+		if err := hub.ValidateInterval(intervals.interval); err != nil {
+			return intervals, apperror.InvalidArgument("interval_seconds: %v", err)
+		}
+	}
+	if len(perTicker) == 0 {
+		return intervals, nil
+	}
+
+	streamed := make(map[string]bool, len(tickers))
+	for _, t := range tickers {
+		streamed[t] = true
+	}
+	intervals.perTicker = make(map[string]time.Duration, len(perTicker))
+	for t, seconds := range perTicker {
+		ticker, err := symbol.Normalize(t)
+		if err != nil {
+			return intervals, err
+		}
+		if !streamed[ticker] {
+			return intervals, apperror.InvalidArgument("ticker_interval_seconds: %s is not one of the streamed tickers", ticker)
+		}
+		interval := time.Duration(seconds) * time.Second
+		if err := hub.ValidateInterval(interval); err != nil {
+			return intervals, apperror.InvalidArgument("ticker_interval_seconds for %s: %v", ticker, err)
+		}
+		intervals.perTicker[ticker] = interval
+	}
+	return intervals, nil
+}
+
+// parseStreamOptions validates the mode of a price stream. The mode defaults
+// to every_tick.
+func parseStreamOptions(mode string, minDeltaAmount, minDeltaPercent float64, keepaliveSeconds int32) (streamOptions, error) {
+	opts := streamOptions{mode: mode}
+	switch mode {
+	case "":
+		opts.mode = modeEveryTick
+	case modeEveryTick, modeOnChange:
+	case modeMinDelta:
+		if minDeltaAmount < 0 || minDeltaPercent < 0 {
+			return opts, apperror.InvalidArgument("min_delta must not be negative")
+		}
+		if (minDeltaAmount > 0) == (minDeltaPercent > 0) {
+			return opts, apperror.InvalidArgument("min_delta mode requires one of min_delta_amount or min_delta_percent")
+		}
+		opts.minDeltaAmount, opts.minDeltaPercent = minDeltaAmount, minDeltaPercent
+	default:
+		return opts, apperror.InvalidArgument("mode must be '%s', '%s' or '%s'", modeEveryTick, modeOnChange, modeMinDelta)
+	}
+	if opts.mode != modeMinDelta && (minDeltaAmount != 0 || minDeltaPercent != 0) {
+		return opts, apperror.InvalidArgument("min_delta_amount and min_delta_percent require min_delta mode")
+	}
+
+	switch {
+	case keepaliveSeconds < 0:
+		return opts, apperror.InvalidArgument("keepalive_seconds must not be negative")
+	case keepaliveSeconds > 0:
+		opts.keepalive = time.Duration(keepaliveSeconds) * time.Second
+	case opts.mode != modeEveryTick:
+		opts.keepalive = defaultKeepalive
+	}
+	return opts, nil
+}
+
+// streamUpdate is a message sent on a price stream: a price with the move
+// since the last one sent for its ticker, or a keepalive
+type streamUpdate struct {
+	pricehub.Update
+	// previous is the last price sent for the ticker, if hasPrevious
+	previous    float64
+	hasPrevious bool
+	keepalive   bool
+}
+
+// change returns the move from the previous price and its percentage
+func (u streamUpdate) change() (float64, float64) {
+	if !u.hasPrevious {
+		return 0, 0
+	}
+	change := u.Price - u.previous
+	if u.previous == 0 {
+		return change, 0
+	}
+	return change, change / u.previous * 100
+}
+
+// priceFilter picks the prices a stream sends according to its mode
+type priceFilter struct {
+	opts streamOptions
+	last map[string]float64
+}
+
+func newPriceFilter(opts streamOptions) *priceFilter {
+	return &priceFilter{opts: opts, last: make(map[string]float64)}
+}
+
+// next returns the update to send for a polled price, if any. The first
+// price of each ticker is always sent.
+func (f *priceFilter) next(u pricehub.Update) (streamUpdate, bool) {
+	previous, hasPrevious := f.last[u.Ticker]
+	update := streamUpdate{Update: u, previous: previous, hasPrevious: hasPrevious}
+	if hasPrevious && !f.moved(previous, u.Price) {
+		return update, false
+	}
+	f.last[u.Ticker] = u.Price
+	return update, true
+}
+
+func (f *priceFilter) moved(previous, price float64) bool {
+	delta := math.Abs(price - previous)
+	switch f.opts.mode {
+	case modeOnChange:
+		return delta != 0
+	case modeMinDelta:
+		if f.opts.minDeltaAmount > 0 {
+			return delta >= f.opts.minDeltaAmount
+		}
+		if previous == 0 {
+			return delta != 0
+		}
+		return delta/math.Abs(previous)*100 >= f.opts.minDeltaPercent
+	default:
+		return true
+	}
+}
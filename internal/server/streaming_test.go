@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+)
+
+func TestParseStreamOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		amount        float64
+		percent       float64
+		keepalive     int32
+		wantMode      string
+		wantKeepalive time.Duration
+		wantErr       bool
+	}{
+		{name: "Default", wantMode: modeEveryTick},
+		{name: "Every tick keepalive", mode: modeEveryTick, keepalive: 10, wantMode: modeEveryTick, wantKeepalive: 10 * time.Second},
+		{name: "On change", mode: modeOnChange, wantMode: modeOnChange, wantKeepalive: defaultKeepalive},
+		{name: "Min delta amount", mode: modeMinDelta, amount: 0.5, wantMode: modeMinDelta, wantKeepalive: defaultKeepalive},
+		{name: "Min delta percent", mode: modeMinDelta, percent: 1, keepalive: 5, wantMode: modeMinDelta, wantKeepalive: 5 * time.Second},
+		{name: "Min delta without delta", mode: modeMinDelta, wantErr: true},
+		{name: "Min delta with both", mode: modeMinDelta, amount: 1, percent: 1, wantErr: true},
+		{name: "Negative delta", mode: modeMinDelta, amount: -1, wantErr: true},
+		{name: "Delta without min delta", mode: modeOnChange, percent: 1, wantErr: true},
+		{name: "Unknown mode", mode: "sometimes", wantErr: true},
+		{name: "Negative keepalive", keepalive: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseStreamOptions(tt.mode, tt.amount, tt.percent, tt.keepalive)
+			if tt.wantErr {
+				if !errors.Is(err, apperror.ErrInvalidArgument) {
+					t.Errorf("parseStreamOptions() error = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStreamOptions() error = %v", err)
+			}
+			if opts.mode != tt.wantMode || opts.keepalive != tt.wantKeepalive {
+				t.Errorf("parseStreamOptions() = %s with keepalive %v, want %s with %v", opts.mode, opts.keepalive, tt.wantMode, tt.wantKeepalive)
+			}
+		})
+	}
+}
+
+func TestPriceFilter(t *testing.T) {
+	prices := []float64{100, 100, 100.4, 101, 100.5, 102}
+
+	tests := []struct {
+		name string
+		opts streamOptions
+		want []float64
+	}{
+		{name: "Every tick", opts: streamOptions{mode: modeEveryTick}, want: prices},
+		{name: "On change", opts: streamOptions{mode: modeOnChange}, want: []float64{100, 100.4, 101, 100.5, 102}},
+		{name: "Min delta amount", opts: streamOptions{mode: modeMinDelta, minDeltaAmount: 1}, want: []float64{100, 101, 102}},
+		{name: "Min delta percent", opts: streamOptions{mode: modeMinDelta, minDeltaPercent: 0.5}, want: []float64{100, 101, 102}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := newPriceFilter(tt.opts)
+			var got []float64
+			for _, price := range prices {
+				if u, ok := filter.next(pricehub.Update{Ticker: "AAPL", Price: price}); ok {
+					got = append(got, u.Price)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("sent %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("sent %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceFilter_Change(t *testing.T) {
+	filter := newPriceFilter(streamOptions{mode: modeOnChange})
+
+	first, _ := filter.next(pricehub.Update{Ticker: "AAPL", Price: 200})
+	if change, percent := first.change(); first.hasPrevious || change != 0 || percent != 0 {
+		t.Errorf("first update = %+v, want no previous price", first)
+	}
+
+	// Other tickers are tracked separately
+	filter.next(pricehub.Update{Ticker: "MSFT", Price: 300})
+
+	update, ok := filter.next(pricehub.Update{Ticker: "AAPL", Price: 210})
+	if !ok {
+		t.Fatal("changed price was not sent")
+	}
+	change, percent := update.change()
+	if update.previous != 200 || change != 10 || math.Abs(percent-5) > 1e-9 {
+		t.Errorf("change = %v (%v%%) from %v, want 10 (5%%) from 200", change, percent, update.previous)
+	}
+}
+
+func TestParseStreamIntervals(t *testing.T) {
+	hub := pricehub.NewHub(nil, pricehub.Options{MinInterval: time.Second, MaxInterval: time.Hour})
+	tickers := []string{"AAPL", "GOOGL"}
+
+	tests := []struct {
+		name      string
+		interval  int32
+		perTicker map[string]int32
+		want      map[string]time.Duration
+		wantErr   bool
+	}{
+		{name: "Default", want: map[string]time.Duration{"AAPL": defaultStreamInterval, "GOOGL": defaultStreamInterval}},
+		{name: "Seconds", interval: 5, want: map[string]time.Duration{"AAPL": 5 * time.Second, "GOOGL": 5 * time.Second}},
+		{name: "Per ticker", interval: 10, perTicker: map[string]int32{"aapl": 60}, want: map[string]time.Duration{"AAPL": time.Minute, "GOOGL": 10 * time.Second}},
+		{name: "Negative", interval: -1, wantErr: true},
+		{name: "Above maximum", interval: 7200, wantErr: true},
+		{name: "Per ticker zero", perTicker: map[string]int32{"AAPL": 0}, wantErr: true},
+		{name: "Per ticker above maximum", perTicker: map[string]int32{"AAPL": 7200}, wantErr: true},
+		{name: "Per ticker not streamed", perTicker: map[string]int32{"MSFT": 10}, wantErr: true},
+		{name: "Per ticker invalid", perTicker: map[string]int32{"$$$": 10}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, err := parseStreamIntervals(hub, tickers, tt.interval, tt.perTicker)
+			if tt.wantErr {
+				if !errors.Is(err, apperror.ErrInvalidArgument) {
+					t.Errorf("parseStreamIntervals() error = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStreamIntervals() error = %v", err)
+			}
+			for ticker, want := range tt.want {
+				if got := intervals.of(ticker); got != want {
+					t.Errorf("interval of %s = %v, want %v", ticker, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStreamIntervals_DefaultClamped(t *testing.T) {
+	tests := []struct {
+		name string
+		opts pricehub.Options
+		want time.Duration
+	}{
+		{name: "Below minimum", opts: pricehub.Options{MinInterval: 10 * time.Second}, want: 10 * time.Second},
+		{name: "Above maximum", opts: pricehub.Options{MaxInterval: 2 * time.Second}, want: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, err := parseStreamIntervals(pricehub.NewHub(nil, tt.opts), []string{"AAPL"}, 0, nil)
+			if err != nil {
+				t.Fatalf("parseStreamIntervals() error = %v", err)
+			}
+			if got := intervals.of("AAPL"); got != tt.want {
+				t.Errorf("default interval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
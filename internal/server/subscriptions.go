@@ -0,0 +1,143 @@
+package server
+
+import (
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+)
+
+// maxSubscriptions bounds the tickers one stream can subscribe to
+const maxSubscriptions = maxBatchTickers
+
+// errNoSubscriptionAction rejects subscription requests that do not either
+// subscribe or unsubscribe
+var errNoSubscriptionAction = apperror.InvalidArgument("exactly one of subscribe or unsubscribe is required")
+
+// tickerError is a ticker that could not be subscribed to or priced
+type tickerError struct {
+	ticker string
+	err    error
+}
+
+// subscriptionSet is the tickers a stream is subscribed to, in the order
+// they were subscribed to
+type subscriptionSet struct {
+	tickers []string
+}
+
+// subscribe adds tickers to the set and returns those that were added.
+// Tickers already in it are left as they are; invalid tickers and tickers
+// past maxSubscriptions are rejected.
+func (s *subscriptionSet) subscribe(raw []string) (added []string, rejected []tickerError) {
+	for _, t := range raw {
+		ticker, err := symbol.Normalize(t)
+		if err != nil {
+			rejected = append(rejected, tickerError{ticker: t, err: err})
+			continue
+		}
+		if s.contains(ticker) {
+			continue
+		}
+		if len(s.tickers) >= maxSubscriptions {
+			rejected = append(rejected, tickerError{
+				ticker: ticker,
+				err:    apperror.InvalidArgument("maximum %d subscriptions per stream", maxSubscriptions),
+			})
+			continue
+		}
+		s.tickers = append(s.tickers, ticker)
+		added = append(added, ticker)
+	}
+	return added, rejected
+}
+
+// unsubscribe removes tickers from the set and returns those that were
+// removed. Tickers not in it are ignored; invalid tickers are rejected.
+func (s *subscriptionSet) unsubscribe(raw []string) (removed []string, rejected []tickerError) {
+	for _, t := range raw {
+		ticker, err := symbol.Normalize(t)
+		if err != nil {
+			rejected = append(rejected, tickerError{ticker: t, err: err})
+			continue
+		}
+		for i, subscribed := range s.tickers {
+			if subscribed == ticker {
+				s.tickers = append(s.tickers[:i], s.tickers[i+1:]...)
+				removed = append(removed, ticker)
+				break
+			}
+		}
+	}
+	return removed, rejected
+}
+
+func (s *subscriptionSet) contains(ticker string) bool {
+	for _, subscribed := range s.tickers {
+		if subscribed == ticker {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns a copy of the subscribed tickers
+func (s *subscriptionSet) list() []string {
+	return append([]string(nil), s.tickers...)
+}
+
+// subscriptionSession is the subscriptions of one Subscribe stream or
+// WebSocket connection, polled through the hub
+type subscriptionSession struct {
+	set      subscriptionSet
+	sub      *pricehub.Subscriber
+	interval time.Duration
+	// failing holds the last error sent for each ticker, so an error is
+	// not repeated on every poll
+	failing map[string]string
+}
+
+func newSubscriptionSession(hub *pricehub.Hub, interval time.Duration) *subscriptionSession {
+	return &subscriptionSession{sub: hub.Subscribe(), interval: interval, failing: make(map[string]string)}
+}
+
+// subscribe adds tickers to the session and returns those rejected
+func (s *subscriptionSession) subscribe(raw []string) []tickerError {
+	added, rejected := s.set.subscribe(raw)
+	for _, ticker := range added {
+		s.sub.Add(ticker, s.interval)
+	}
+	return rejected
+}
+
+// unsubscribe removes tickers from the session and returns those rejected
+func (s *subscriptionSession) unsubscribe(raw []string) []tickerError {
+	removed, rejected := s.set.unsubscribe(raw)
+	for _, ticker := range removed {
+		s.sub.Remove(ticker)
+		delete(s.failing, ticker)
+	}
+	return rejected
+}
+
+// deliver reports whether an update is to be sent. Updates polled before a
+// ticker was unsubscribed from and errors already sent are not.
+func (s *subscriptionSession) deliver(u pricehub.Update) bool {
+	if !s.set.contains(u.Ticker) {
+		return false
+	}
+	if u.Err == nil {
+		delete(s.failing, u.Ticker)
+		return true
+	}
+	if s.failing[u.Ticker] == u.Err.Error() {
+		return false
+	}
+	s.failing[u.Ticker] = u.Err.Error()
+	return true
+}
+
+func (s *subscriptionSession) close() {
+	s.sub.Close()
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket keepalive: a ping is sent every wsPingInterval and the
+// connection is closed when no pong arrives within wsPongWait. A write that
+// takes longer than wsWriteWait closes it too, so a client that stops
+// reading is let go.
+var (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsWriteWait    = 10 * time.Second
+)
+
+// wsMaxMessageSize bounds the messages clients send
+const wsMaxMessageSize = 4096
+
+var upgrader = websocket.Upgrader{}
+
+// wsRequest is a message read from a WebSocket, or the reason it could not
+// be decoded
+type wsRequest struct {
+	req types.SubscribeRequest
+	err error
+}
+
+// handleWebSocketPrices streams prices over a WebSocket with the protocol
+// of the gRPC v2 Subscribe stream, as JSON messages
+func (s *JSONAPIServer) handleWebSocketPrices(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	// Messages are read on their own goroutine; all writes happen below
+	requests := make(chan wsRequest)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			var in wsRequest
+			if err := json.Unmarshal(data, &in.req); err != nil {
+				in.err = apperror.InvalidArgument("invalid message: %v", err)
+			}
+			select {
+			case requests <- in:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	session := newSubscriptionSession(s.hub, subscribeInterval)
+	defer session.close()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	send := func(msg types.SubscribeResponse) error {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		return conn.WriteJSON(msg)
+	}
+
+	for {
+		select {
+		case in := <-requests:
+			var rejected []tickerError
+			switch {
+			case in.err != nil:
+				rejected = []tickerError{{err: in.err}}
+			case in.req.Subscribe != nil && in.req.Unsubscribe == nil:
+				rejected = session.subscribe(in.req.Subscribe.Tickers)
+			case in.req.Unsubscribe != nil && in.req.Subscribe == nil:
+				rejected = session.unsubscribe(in.req.Unsubscribe.Tickers)
+			default:
+				rejected = []tickerError{{err: errNoSubscriptionAction}}
+			}
+
+			ack := &types.SubscriptionAck{RequestID: in.req.RequestID, Tickers: session.set.list()}
+			for _, r := range rejected {
+				ack.Errors = append(ack.Errors, toJSONTickerError(r.ticker, r.err))
+			}
+			if err := send(types.SubscribeResponse{Ack: ack}); err != nil {
+				return
+			}
+
+		case u := <-session.sub.Updates():
+			if !session.deliver(u) {
+				continue
+			}
+
+			msg := types.SubscribeResponse{Price: &types.StreamPrice{
+				Ticker:    u.Ticker,
+				Price:     u.Price,
+				Currency:  tickerCurrency(u.Ticker),
+				Timestamp: u.Time.Format(time.RFC3339),
+				Source:    u.Meta.Source,
+				Stale:     u.Meta.Stale,
+				AsOf:      formatAsOf(u.Meta.AsOf),
+			}}
+			if u.Err != nil {
+				tickerErr := toJSONTickerError(u.Ticker, u.Err)
+				msg = types.SubscribeResponse{Error: &tickerErr}
+			}
+			if err := send(msg); err != nil {
+				return
+			}
+
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+
+		case <-session.sub.Dropped():
+			closeWebSocket(conn, websocket.CloseTryAgainLater, pricehub.ErrSlowConsumer.Error())
+			return
+
+		case <-s.shutdown:
+			closeWebSocket(conn, websocket.CloseGoingAway, "server shutting down")
+			return
+
+		case <-readErr:
+			// The client closed the connection or stopped answering pings
+			return
+		}
+	}
+}
+
+// closeWebSocket sends a close frame. The connection is closed by the
+// caller without waiting for the client's reply.
+func closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+}
+
+func toJSONTickerError(ticker string, err error) types.TickerError {
+	return types.TickerError{
+		Ticker:  ticker,
+		Code:    apperror.GRPCCode(err).String(),
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/pricehub"
+	"github.com/aliexe/ms-priceFetcher/internal/service"
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	"github.com/gorilla/websocket"
+)
+
+// newTestJSONServer serves the mock prices, polling every 10ms. Prices
+// streamed are recorded to tickStore unless it is nil.
+func newTestJSONServer(t *testing.T, tickStore *ticks.Store) (*JSONAPIServer, string) {
+	t.Helper()
+	interval := subscribeInterval
+	subscribeInterval = 10 * time.Millisecond
+	t.Cleanup(func() { subscribeInterval = interval })
+
+	svc := service.NewPriceService()
+	streamed := svc
+	if tickStore != nil {
+		streamed = service.NewRecordingService(svc, tickStore)
+	}
+	s := NewJSONAPIServer(":0", svc, service.NewAlertService(svc), tickStore, svc.(service.RateService), pricehub.NewHub(streamed, pricehub.Options{}))
+	httpServer := httptest.NewServer(s.Handler())
+	t.Cleanup(httpServer.Close)
+
+	return s, httpServer.URL
+}
+
+func dialPrices(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/ws/prices", nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) types.SubscribeResponse {
+	t.Helper()
+	var msg types.SubscribeResponse
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return msg
+}
+
+// readAck reads messages until the next ack, skipping prices sent before it
+func readAck(t *testing.T, conn *websocket.Conn) *types.SubscriptionAck {
+	t.Helper()
+	for {
+		if msg := readMessage(t, conn); msg.Ack != nil {
+			return msg.Ack
+		}
+	}
+}
+
+func TestWebSocketPrices(t *testing.T) {
+	_, url := newTestJSONServer(t, nil)
+	conn := dialPrices(t, url)
+
+	err := conn.WriteJSON(types.SubscribeRequest{RequestID: "1", Subscribe: &types.TickerList{Tickers: []string{"aapl", "ZZZZ", ""}}})
+	if err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	ack := readAck(t, conn)
+	if ack.RequestID != "1" || len(ack.Tickers) != 2 || ack.Tickers[0] != "AAPL" || ack.Tickers[1] != "ZZZZ" {
+		t.Errorf("ack = %+v, want AAPL and ZZZZ subscribed", ack)
+	}
+	if len(ack.Errors) != 1 || ack.Errors[0].Code != "InvalidArgument" {
+		t.Errorf("ack errors = %+v, want the empty ticker rejected", ack.Errors)
+	}
+
+	// Prices keep coming; the unknown ticker's error is sent once
+	var prices, errs int
+	for prices < 3 {
+		msg := readMessage(t, conn)
+		switch {
+		case msg.Price != nil:
+			if msg.Price.Ticker != "AAPL" || msg.Price.Price != 150 || msg.Price.Currency != "USD" {
+				t.Errorf("price = %+v, want AAPL at 150 USD", msg.Price)
+			}
+			prices++
+		case msg.Error != nil:
+			if msg.Error.Ticker != "ZZZZ" || msg.Error.Code != "NotFound" {
+				t.Errorf("error = %+v, want ZZZZ not found", msg.Error)
+			}
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors for ZZZZ, want 1", errs)
+	}
+
+	err = conn.WriteJSON(types.SubscribeRequest{RequestID: "2", Unsubscribe: &types.TickerList{Tickers: []string{"AAPL", "ZZZZ"}}})
+	if err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if ack := readAck(t, conn); ack.RequestID != "2" || len(ack.Tickers) != 0 {
+		t.Errorf("ack = %+v, want nothing subscribed", ack)
+	}
+
+	// Nothing more is sent once every ticker is unsubscribed from
+	conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	var msg types.SubscribeResponse
+	if err := conn.ReadJSON(&msg); err == nil {
+		t.Errorf("got %+v after unsubscribing", msg)
+	}
+}
+
+func TestWebSocketPrices_InvalidRequests(t *testing.T) {
+	_, url := newTestJSONServer(t, nil)
+	conn := dialPrices(t, url)
+
+	requests := []string{
+		`not json`,
+		`{"request_id":"empty"}`,
+		`{"subscribe":{"tickers":["AAPL"]},"unsubscribe":{"tickers":["AAPL"]}}`,
+	}
+	for _, req := range requests {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+			t.Fatalf("WriteMessage() error = %v", err)
+		}
+		ack := readAck(t, conn)
+		if len(ack.Tickers) != 0 || len(ack.Errors) != 1 || ack.Errors[0].Code != "InvalidArgument" {
+			t.Errorf("ack for %s = %+v, want the request rejected", req, ack)
+		}
+	}
+}
+
+func TestWebSocketPrices_SubscriptionCap(t *testing.T) {
+	_, url := newTestJSONServer(t, nil)
+	conn := dialPrices(t, url)
+
+	tickers := make([]string, maxSubscriptions+1)
+	for i := range tickers {
+		tickers[i] = fmt.Sprintf("T%d", i)
+	}
+	if err := conn.WriteJSON(types.SubscribeRequest{Subscribe: &types.TickerList{Tickers: tickers}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	ack := readAck(t, conn)
+	if len(ack.Tickers) != maxSubscriptions {
+		t.Errorf("subscribed to %d tickers, want %d", len(ack.Tickers), maxSubscriptions)
+	}
+	if len(ack.Errors) != 1 || ack.Errors[0].Ticker != tickers[maxSubscriptions] {
+		t.Errorf("ack errors = %+v, want %s rejected", ack.Errors, tickers[maxSubscriptions])
+	}
+}
+
+func TestWebSocketPrices_Ping(t *testing.T) {
+	ping := wsPingInterval
+	wsPingInterval = 10 * time.Millisecond
+	t.Cleanup(func() { wsPingInterval = ping })
+
+	_, url := newTestJSONServer(t, nil)
+	conn := dialPrices(t, url)
+
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	// Control frames are handled while reading
+	go conn.ReadMessage()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-pings:
+		case <-time.After(time.Second):
+			t.Fatal("no ping received")
+		}
+	}
+}
+
+func TestWebSocketPrices_Shutdown(t *testing.T) {
+	s, url := newTestJSONServer(t, nil)
+	conn := dialPrices(t, url)
+
+	if err := conn.WriteJSON(types.SubscribeRequest{Subscribe: &types.TickerList{Tickers: []string{"AAPL"}}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	readAck(t, conn)
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("ReadMessage() error = %v, want going away", err)
+		}
+		break
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import "github.com/aliexe/ms-priceFetcher/pkg/types"
+
+// AdjustBars returns the bars with open, high, low and close scaled for
+// later splits and dividends, so that returns computed across a corporate
+// action are correct. Each bar is scaled by the ratio of its adjusted close
+// to its close. Volume is left as traded. Bars without an adjusted close,
+// such as intraday bars, are returned unchanged.
+func AdjustBars(points []types.HistoricalPricePoint) []types.HistoricalPricePoint {
+	adjusted := make([]types.HistoricalPricePoint, len(points))
+	for i, p := range points {
+		if p.AdjustedClose > 0 && p.Close > 0 {
+			factor := p.AdjustedClose / p.Close
+			p.Open *= factor
+			p.High *= factor
+			p.Low *= factor
+			p.Close = p.AdjustedClose
+		}
+		adjusted[i] = p
+	}
+	return adjusted
+}
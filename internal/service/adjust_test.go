@@ -0,0 +1,40 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+func TestAdjustBars(t *testing.T) {
+	// Apple traded around $500 before its 4-for-1 split on 2020-08-31
+	raw := []types.HistoricalPricePoint{
+		{Date: "2020-08-28", Open: 504, High: 505, Low: 498, Close: 500, Volume: 200, AdjustedClose: 125},
+		{Date: "2020-08-31", Open: 127, High: 131, Low: 126, Close: 129, Volume: 800, AdjustedClose: 129, SplitCoefficient: 4},
+		{Date: "2024-01-03T09:30:00-05:00", Open: 1, High: 2, Low: 1, Close: 2, Volume: 10},
+	}
+
+	adjusted := AdjustBars(raw)
+
+	want := []types.HistoricalPricePoint{
+		{Date: "2020-08-28", Open: 126, High: 126.25, Low: 124.5, Close: 125, Volume: 200, AdjustedClose: 125},
+		{Date: "2020-08-31", Open: 127, High: 131, Low: 126, Close: 129, Volume: 800, AdjustedClose: 129, SplitCoefficient: 4},
+		{Date: "2024-01-03T09:30:00-05:00", Open: 1, High: 2, Low: 1, Close: 2, Volume: 10},
+	}
+	for i := range want {
+		got := adjusted[i]
+		if math.Abs(got.Open-want[i].Open) > 1e-9 || math.Abs(got.High-want[i].High) > 1e-9 ||
+			math.Abs(got.Low-want[i].Low) > 1e-9 || got.Close != want[i].Close || got.Volume != want[i].Volume {
+			t.Errorf("AdjustBars()[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// The return across the split reflects the move, not the share count
+	if ret := adjusted[1].Close/adjusted[0].Close - 1; math.Abs(ret-0.032) > 1e-9 {
+		t.Errorf("Return across split = %v, want 0.032", ret)
+	}
+	if raw[0].Close != 500 {
+		t.Error("AdjustBars() modified its input")
+	}
+}
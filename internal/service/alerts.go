@@ -9,6 +9,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
 	"github.com/sirupsen/logrus"
 )
 
@@ -55,6 +57,17 @@ func NewAlertService(priceSvc PriceService) *AlertService {
 
 // CreateAlert creates a new price alert
 func (s *AlertService) CreateAlert(ticker string, condition AlertCondition, threshold float64, webhookURL string) (*Alert, error) {
+	ticker, err := symbol.Normalize(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if condition != ConditionAbove && condition != ConditionBelow {
+		return nil, apperror.InvalidArgument("condition must be '%s' or '%s'", ConditionAbove, ConditionBelow)
+	}
+	if threshold <= 0 {
+		return nil, apperror.InvalidArgument("threshold must be positive")
+	}
+
 	s.alertsMutex.Lock()
 	defer s.alertsMutex.Unlock()
 
@@ -87,7 +100,7 @@ func (s *AlertService) GetAlert(alertID string) (*Alert, error) {
 
 	alert, exists := s.alerts[alertID]
 	if !exists {
-		return nil, fmt.Errorf("alert not found: %s", alertID)
+		return nil, apperror.NotFound("alert not found: %s", alertID)
 	}
 
 	return alert, nil
@@ -112,7 +125,7 @@ func (s *AlertService) DeleteAlert(alertID string) error {
 	defer s.alertsMutex.Unlock()
 
 	if _, exists := s.alerts[alertID]; !exists {
-		return fmt.Errorf("alert not found: %s", alertID)
+		return apperror.NotFound("alert not found: %s", alertID)
 	}
 
 	delete(s.alerts, alertID)
@@ -123,6 +136,9 @@ func (s *AlertService) DeleteAlert(alertID string) error {
 
 // CheckAlerts evaluates all active alerts against current prices
 func (s *AlertService) CheckAlerts(ctx context.Context) error {
+	// Alert checks yield upstream quota to interactive requests
+	ctx = WithPriority(ctx, PriorityBackground)
+
 	s.alertsMutex.RLock()
 	defer s.alertsMutex.RUnlock()
 
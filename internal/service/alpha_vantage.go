@@ -3,15 +3,24 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/cache"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
 	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	"github.com/sirupsen/logrus"
 )
 
 // AlphaVantageResponse represents the API response from Alpha Vantage
@@ -35,26 +44,134 @@ type GlobalQuote struct {
 
 // AlphaVantageService implements real-time stock price fetching
 type AlphaVantageService struct {
-	apiKey        string
-	baseURL       string
-	httpClient    *http.Client
-	cache         map[string]cacheEntry
-	cacheMutex    sync.RWMutex
-	cacheTTL      time.Duration
-	maxCacheSize  int
+	apiKey               string
+	baseURL              string
+	httpClient           *http.Client
+	cache                cache.Cache
+	cacheTTL             time.Duration
+	staleWhileRevalidate time.Duration
+	maxStale             time.Duration
+	limiter              *RateLimiter
+	priceFlight          flightGroup[float64]
+	quoteFlight          flightGroup[types.Quote]
+	searchFlight         flightGroup[[]types.SymbolInfo]
+	symbolFlight         flightGroup[types.SymbolInfo]
+	rateFlight           flightGroup[float64]
+	rateHistoryFlight    flightGroup[map[string]float64]
+	historyFlight        flightGroup[[]types.HistoricalPricePoint]
+	history              history.Store
+	syncFlight           flightGroup[history.Series]
 }
 
-type cacheEntry struct {
-	price    float64
-	history  []types.HistoricalPricePoint
-	expiry   time.Time
+// alphaVantageEnvelope holds the fields Alpha Vantage uses to report errors
+type alphaVantageEnvelope struct {
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+	ErrorMessage string `json:"Error Message"`
 }
+
+// Kinds of errors reported by Alpha Vantage in an otherwise successful response
+var (
+	ErrRateLimited   = apperror.New(apperror.ErrRateLimited, "upstream rate limit reached")
+	ErrInvalidSymbol = apperror.New(apperror.ErrNotFound, "invalid symbol")
+	ErrInvalidAPIKey = apperror.New(apperror.ErrUpstreamUnavailable, "invalid API key")
+	// ErrUnsupportedEndpoint is a premium or unknown function, which no
+	// amount of retrying will unlock
+	ErrUnsupportedEndpoint = apperror.New(apperror.ErrUpstreamUnavailable, "endpoint not available")
+)
+
+// AlphaVantageError is an error envelope returned by Alpha Vantage
+type AlphaVantageError struct {
+	Kind    error
+	Message string
+}
+
+func (e *AlphaVantageError) Error() string {
+	return fmt.Sprintf("alpha vantage: %v: %s", e.Kind, e.Message)
+}
+
+func (e *AlphaVantageError) Unwrap() error {
+	return e.Kind
+}
+
+// cachedValue is a cached upstream answer along with when it was fetched
+// and when it stops being fresh
+type cachedValue[T any] struct {
+	Value    T         `json:"value"`
+	StoredAt time.Time `json:"stored_at"`
+	Expiry   time.Time `json:"expiry"`
+}
+
 const (
 	defaultMaxCacheSize = 1000 // Maximum number of cached entries
+
+	// Stale values are served for this long past cacheTTL while a
+	// background refresh runs, and for up to defaultMaxStale after being
+	// fetched when upstream fails
+	defaultStaleWhileRevalidate = time.Minute
+	defaultMaxStale             = time.Hour
+	revalidateTimeout           = 30 * time.Second
+
+	// Symbols are rarely renamed or relisted, so searches and metadata
+	// are kept far longer than prices
+	symbolCacheTTL = 24 * time.Hour
+
+	// Daily FX series only gain a bar a day
+	rateHistoryCacheTTL = time.Hour
+
+	// A compact daily series holds the latest 100 trading days. Stored
+	// histories last updated within this span are topped up with one.
+	compactSpan = 120 * 24 * time.Hour
+
+	// Free tier quotas
+	defaultRequestsPerMinute = 5
+	defaultRequestsPerDay    = 25
 )
 
+// AlphaVantageOptions holds Alpha Vantage's request budgets and how long
+// stale cache values may be served
+type AlphaVantageOptions struct {
+	// RequestsPerMinute and RequestsPerDay are the request budgets shared
+	// by all upstream calls. Zero disables a limit.
+	RequestsPerMinute int
+	RequestsPerDay    int
+	// StaleWhileRevalidate is how long past the cache TTL a stale value is
+	// returned while a background refresh runs
+	StaleWhileRevalidate time.Duration
+	// MaxStale is the age up to which the last known value is served when
+	// upstream fails
+	MaxStale time.Duration
+}
+
+// DefaultAlphaVantageOptions returns the free tier budgets and the default
+// staleness windows
+func DefaultAlphaVantageOptions() AlphaVantageOptions {
+	return AlphaVantageOptions{
+		RequestsPerMinute:    defaultRequestsPerMinute,
+		RequestsPerDay:       defaultRequestsPerDay,
+		StaleWhileRevalidate: defaultStaleWhileRevalidate,
+		MaxStale:             defaultMaxStale,
+	}
+}
+
 // NewAlphaVantageService creates a new Alpha Vantage service instance
+// with an in-memory cache and no history store
 func NewAlphaVantageService() *AlphaVantageService {
+	return NewAlphaVantageServiceWithOptions(ProviderOptions{})
+}
+
+// NewAlphaVantageServiceWithOptions creates a new Alpha Vantage service
+// instance using the shared resources in opts
+func NewAlphaVantageServiceWithOptions(opts ProviderOptions) *AlphaVantageService {
+	c := opts.Cache
+	if c == nil {
+		c = cache.NewLRU(defaultMaxCacheSize)
+	}
+	avOpts := DefaultAlphaVantageOptions()
+	if opts.AlphaVantage != nil {
+		avOpts = *opts.AlphaVantage
+	}
+
 	apiKey := os.Getenv("ALPHA_VANTAGE_API_KEY")
 	if apiKey == "" {
 		apiKey = "demo" // Demo key for testing
@@ -67,6 +184,8 @@ func NewAlphaVantageService() *AlphaVantageService {
 		IdleConnTimeout:     90 * time.Second,
 	}
 
+	limiter := NewRateLimiter(avOpts.RequestsPerMinute, avOpts.RequestsPerDay)
+
 	return &AlphaVantageService{
 		apiKey:  apiKey,
 		baseURL: "https://www.alphavantage.co/query",
@@ -74,79 +193,351 @@ func NewAlphaVantageService() *AlphaVantageService {
 			Timeout:   10 * time.Second,
 			Transport: transport,
 		},
-		cache:         make(map[string]cacheEntry),
-		cacheTTL:      5 * time.Minute, // Cache prices for 5 minutes
-		maxCacheSize:  defaultMaxCacheSize,
+		cache:                c,
+		cacheTTL:             5 * time.Minute, // Cache prices for 5 minutes
+		staleWhileRevalidate: avOpts.StaleWhileRevalidate,
+		maxStale:             avOpts.MaxStale,
+		priceFlight:          flightGroup[float64]{metric: "alphavantage_price", limiter: limiter},
+		quoteFlight:          flightGroup[types.Quote]{metric: "alphavantage_quote", limiter: limiter},
+		searchFlight:         flightGroup[[]types.SymbolInfo]{metric: "alphavantage_symbol_search", limiter: limiter},
+		symbolFlight:         flightGroup[types.SymbolInfo]{metric: "alphavantage_symbol", limiter: limiter},
+		rateFlight:           flightGroup[float64]{metric: "alphavantage_fx_rate", limiter: limiter},
+		rateHistoryFlight:    flightGroup[map[string]float64]{metric: "alphavantage_fx_history", limiter: limiter},
+		historyFlight:        flightGroup[[]types.HistoricalPricePoint]{metric: "alphavantage_history", limiter: limiter},
+		history:              opts.History,
+		syncFlight:           flightGroup[history.Series]{metric: "alphavantage_history_sync", limiter: limiter},
+		limiter:              limiter,
 	}
 }
 
 // FetchPrice retrieves the current stock price from Alpha Vantage API
 func (s *AlphaVantageService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
-	// Check cache first
-	if price, found := s.getCachedPrice(ticker); found {
-		return price, nil
+	return serveCached(ctx, s, ticker, ticker, s.prices(), &s.priceFlight,
+		func(ctx context.Context) (float64, error) { return s.fetchPrice(ctx, ticker) },
+	)
+}
+
+// fetchPrice requests the current quote from the API and returns its price
+func (s *AlphaVantageService) fetchPrice(ctx context.Context, ticker string) (float64, error) {
+	quote, err := s.fetchQuote(ctx, ticker)
+	if err != nil {
+		return 0, err
+	}
+	return quote.Price, nil
+}
+
+// FetchQuote retrieves the latest session's quote from Alpha Vantage API
+func (s *AlphaVantageService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	return serveCached(ctx, s, ticker, ticker, s.quotes(), &s.quoteFlight,
+		func(ctx context.Context) (types.Quote, error) { return s.fetchQuote(ctx, ticker) },
+	)
+}
+
+// fetchQuote requests the current quote from the API. Both the quote and
+// its price are cached, as they come from the same call.
+func (s *AlphaVantageService) fetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	avSymbol, err := alphaVantageSymbol(ticker)
+	if err != nil {
+		return types.Quote{}, err
 	}
 
 	// Build request URL
 	params := url.Values{}
 	params.Set("function", "GLOBAL_QUOTE")
-	params.Set("symbol", ticker)
-	params.Set("apikey", s.apiKey)
+	params.Set("symbol", avSymbol)
 
-	reqURL := fmt.Sprintf("%s?%s", s.baseURL, params.Encode())
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return types.Quote{}, err
+	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	var avResponse AlphaVantageResponse
+	if err := json.Unmarshal(body, &avResponse); err != nil {
+		return types.Quote{}, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
+	}
+
+	// Alpha Vantage answers unknown symbols with an empty quote
+	if avResponse.GlobalQuote.Price == "" {
+		return types.Quote{}, &AlphaVantageError{Kind: ErrInvalidSymbol, Message: fmt.Sprintf("no quote returned for %s", ticker)}
+	}
+
+	quote, err := avResponse.GlobalQuote.toQuote(ticker)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return types.Quote{}, err
 	}
 
-	// Execute request
-	resp, err := s.httpClient.Do(req)
+	// Cache the quote and price
+	storeCached(ctx, s, s.quotes(), ticker, quote)
+	storeCached(ctx, s, s.prices(), ticker, quote.Price)
+
+	return quote, nil
+}
+
+// toQuote converts the string fields of a GLOBAL_QUOTE response. Only the
+// price is required; other malformed fields are left at zero.
+func (q GlobalQuote) toQuote(ticker string) (types.Quote, error) {
+	price, err := parsePrice(q.Price)
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch price: %w", err)
+		return types.Quote{}, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse price")
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	open, _ := parsePrice(q.Open)
+	high, _ := parsePrice(q.High)
+	low, _ := parsePrice(q.Low)
+	volume, _ := strconv.ParseInt(q.Volume, 10, 64)
+	previousClose, _ := parsePrice(q.PreviousClose)
+	change, _ := parsePrice(q.Change)
+	changePercent, _ := parsePrice(strings.TrimSuffix(q.ChangePercent, "%"))
+
+	return types.Quote{
+		Ticker:           ticker,
+		Open:             open,
+		High:             high,
+		Low:              low,
+		Price:            price,
+		Volume:           volume,
+		LatestTradingDay: q.LatestTradingDay,
+		PreviousClose:    previousClose,
+		Change:           change,
+		ChangePercent:    changePercent,
+	}, nil
+}
+
+// symbolMatch is one result of a SYMBOL_SEARCH response
+type symbolMatch struct {
+	Symbol     string `json:"1. symbol"`
+	Name       string `json:"2. name"`
+	Type       string `json:"3. type"`
+	Region     string `json:"4. region"`
+	Currency   string `json:"8. currency"`
+	MatchScore string `json:"9. matchScore"`
+}
+
+// SearchSymbols finds symbols matching a symbol or company name
+func (s *AlphaVantageService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+	return serveCached(ctx, s, query, key, s.symbolSearches(), &s.searchFlight,
+		func(ctx context.Context) ([]types.SymbolInfo, error) { return s.searchSymbols(ctx, key) },
+	)
+}
+
+// searchSymbols requests the matches for query from the API and caches them
+func (s *AlphaVantageService) searchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	params := url.Values{}
+	params.Set("function", "SYMBOL_SEARCH")
+	params.Set("keywords", query)
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse response
-	body, err := io.ReadAll(resp.Body)
+	var avResponse struct {
+		BestMatches []symbolMatch `json:"bestMatches"`
+	}
+	if err := json.Unmarshal(body, &avResponse); err != nil {
+		return nil, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
+	}
+
+	// Search results do not name the exchange; only listings outside the
+	// US carry it in their suffix
+	matches := make([]types.SymbolInfo, 0, len(avResponse.BestMatches))
+	for _, m := range avResponse.BestMatches {
+		sym, err := symbol.Parse(m.Symbol)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"symbol": m.Symbol,
+				"error":  err,
+			}).Debug("Skipping unsupported search result")
+			continue
+		}
+
+		score, _ := parsePrice(m.MatchScore)
+		matches = append(matches, types.SymbolInfo{
+			Symbol:     sym.String(),
+			Name:       m.Name,
+			Exchange:   sym.ExchangeCode(),
+			Type:       m.Type,
+			Region:     m.Region,
+			Currency:   m.Currency,
+			MatchScore: score,
+		})
+	}
+	sortMatches(matches)
+
+	storeCachedFor(ctx, s, s.symbolSearches(), query, matches, symbolCacheTTL)
+	return matches, nil
+}
+
+// FetchSymbol returns the metadata of ticker
+func (s *AlphaVantageService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	key := strings.ToUpper(ticker)
+	return serveCached(ctx, s, ticker, key, s.symbols(), &s.symbolFlight,
+		func(ctx context.Context) (types.SymbolInfo, error) { return s.fetchSymbol(ctx, key) },
+	)
+}
+
+// fetchSymbol looks ticker up by searching for it. US listings are missing
+// their exchange in search results, so it is taken from the company
+// overview when available.
+func (s *AlphaVantageService) fetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	// Search for the symbol as Alpha Vantage lists it
+	avSymbol, err := alphaVantageSymbol(ticker)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
+		return types.SymbolInfo{}, err
+	}
+	matches, err := s.SearchSymbols(ctx, avSymbol)
+	if err != nil {
+		return types.SymbolInfo{}, err
 	}
 
-	var avResponse AlphaVantageResponse
+	var info types.SymbolInfo
+	found := false
+	for _, m := range matches {
+		if strings.EqualFold(m.Symbol, ticker) {
+			info, found = m, true
+			break
+		}
+	}
+	if !found {
+		return types.SymbolInfo{}, apperror.NotFound("unknown symbol: %s", ticker)
+	}
+	info.MatchScore = 0
+
+	if info.Exchange == "" {
+		exchange, err := s.fetchExchange(ctx, info.Symbol)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"ticker": ticker,
+				"error":  err,
+			}).Warn("Failed to look up exchange")
+		}
+		info.Exchange = exchange
+	}
+
+	storeCachedFor(ctx, s, s.symbols(), ticker, info, symbolCacheTTL)
+	return info, nil
+}
+
+// fetchExchange returns the exchange named in the company overview. Funds
+// and other listings without an overview have no exchange.
+func (s *AlphaVantageService) fetchExchange(ctx context.Context, ticker string) (string, error) {
+	avSymbol, err := alphaVantageSymbol(ticker)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("function", "OVERVIEW")
+	params.Set("symbol", avSymbol)
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	var overview struct {
+		Exchange string `json:"Exchange"`
+	}
+	if err := json.Unmarshal(body, &overview); err != nil {
+		return "", apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
+	}
+	return overview.Exchange, nil
+}
+
+// FetchRate retrieves the current exchange rate between two currencies
+func (s *AlphaVantageService) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	pair := from + "/" + to
+	return serveCached(ctx, s, pair, pair, s.rates(), &s.rateFlight,
+		func(ctx context.Context) (float64, error) { return s.fetchRate(ctx, from, to) },
+	)
+}
+
+// fetchRate requests the realtime exchange rate from the API and caches it
+func (s *AlphaVantageService) fetchRate(ctx context.Context, from, to string) (float64, error) {
+	params := url.Values{}
+	params.Set("function", "CURRENCY_EXCHANGE_RATE")
+	params.Set("from_currency", from)
+	params.Set("to_currency", to)
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	var avResponse struct {
+		Rate map[string]interface{} `json:"Realtime Currency Exchange Rate"`
+	}
 	if err := json.Unmarshal(body, &avResponse); err != nil {
-		return 0, fmt.Errorf("failed to parse response: %w", err)
+		return 0, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
 	}
 
-	// Extract price
-	if avResponse.GlobalQuote.Price == "" {
-		return 0, fmt.Errorf("invalid response: price field is empty for ticker %s", ticker)
+	rate, err := parsePrice(barField(avResponse.Rate, "Exchange Rate"))
+	if err != nil || rate <= 0 {
+		return 0, &AlphaVantageError{Kind: ErrInvalidSymbol, Message: fmt.Sprintf("no exchange rate returned for %s/%s", from, to)}
 	}
 
-	var price float64
-	_, err = fmt.Sscanf(avResponse.GlobalQuote.Price, "%f", &price)
+	storeCached(ctx, s, s.rates(), from+"/"+to, rate)
+	return rate, nil
+}
+
+// FetchRateHistory retrieves the daily closing rates between two currencies
+// within the range. The full series is cached and filtered per request.
+func (s *AlphaVantageService) FetchRateHistory(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error) {
+	pair := from + "/" + to
+	series, err := serveCached(ctx, s, pair, pair, s.rateHistories(), &s.rateHistoryFlight,
+		func(ctx context.Context) (map[string]float64, error) { return s.fetchRateHistory(ctx, from, to) },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64)
+	for date, rate := range series {
+		if (fromDate == "" || date >= fromDate) && (toDate == "" || date <= toDate) {
+			rates[date] = rate
+		}
+	}
+	return rates, nil
+}
+
+// fetchRateHistory requests the full daily FX series from the API and
+// caches it
+func (s *AlphaVantageService) fetchRateHistory(ctx context.Context, from, to string) (map[string]float64, error) {
+	params := url.Values{}
+	params.Set("function", "FX_DAILY")
+	params.Set("from_symbol", from)
+	params.Set("to_symbol", to)
+	params.Set("outputsize", "full")
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := parseTimeSeries(body, "Time Series FX (Daily)", from+"/"+to, func(date string) (string, error) {
+		return date, nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse price: %w", err)
+		return nil, err
 	}
 
-	// Cache the price
-	s.setCachedPrice(ticker, price)
+	rates := make(map[string]float64, len(points))
+	for _, p := range points {
+		if p.Close > 0 {
+			rates[p.Date] = p.Close
+		}
+	}
 
-	return price, nil
+	storeCachedFor(ctx, s, s.rateHistories(), from+"/"+to, rates, rateHistoryCacheTTL)
+	return rates, nil
 }
 
 // FetchPrices retrieves multiple stock prices concurrently
 func (s *AlphaVantageService) FetchPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
 	results := make(map[string]float64)
 	resultsMutex := sync.Mutex{}
-	errors := make([]error, 0)
+	errs := make([]error, 0)
 	errorsMutex := sync.Mutex{}
 
 	var wg sync.WaitGroup
@@ -162,7 +553,7 @@ func (s *AlphaVantageService) FetchPrices(ctx context.Context, tickers []string)
 			price, err := s.FetchPrice(ctx, t)
 			if err != nil {
 				errorsMutex.Lock()
-				errors = append(errors, err)
+				errs = append(errs, err)
 				errorsMutex.Unlock()
 				return
 			}
@@ -175,28 +566,329 @@ func (s *AlphaVantageService) FetchPrices(ctx context.Context, tickers []string)
 
 	wg.Wait()
 
-	if len(results) == 0 && len(errors) > 0 {
-		return nil, fmt.Errorf("failed to fetch prices for any ticker: %v", errors)
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to fetch prices for any ticker: %w", errors.Join(errs...))
 	}
 
 	return results, nil
 }
 
 // FetchPriceHistory retrieves historical price data for a ticker
-func (s *AlphaVantageService) FetchPriceHistory(ctx context.Context, ticker, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
-	// Check cache first with a unique key
-	cacheKey := fmt.Sprintf("history_%s_%s_%s", ticker, fromDate, toDate)
-	if cached, found := s.getCachedHistory(cacheKey); found {
-		return cached, nil
+func (s *AlphaVantageService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	if interval == "" {
+		interval = IntervalDaily
+	}
+
+	// Normalise the bounds so equivalent ranges share a cache entry
+	if interval.Intraday() {
+		if err := errors.Join(ValidateHistoryBound(fromDate), ValidateHistoryBound(toDate)); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if fromDate, err = dateBound(fromDate); err != nil {
+			return nil, err
+		}
+		if toDate, err = dateBound(toDate); err != nil {
+			return nil, err
+		}
+	}
+
+	cacheKey := fmt.Sprintf("%s_%s_%s_%s", ticker, interval, fromDate, toDate)
+	return serveCached(ctx, s, ticker, cacheKey, s.histories(), &s.historyFlight,
+		func(ctx context.Context) ([]types.HistoricalPricePoint, error) {
+			return s.fetchPriceHistory(ctx, ticker, interval, fromDate, toDate, cacheKey)
+		},
+	)
+}
+
+// serveCached answers from the cache when it can. Fresh entries are returned
+// as is. Within the stale-while-revalidate window the stale value is
+// returned while a background refresh runs. Otherwise the caller waits for
+// upstream, falling back to a value up to maxStale old if upstream fails.
+// Concurrent upstream calls for the same key are coalesced through group.
+func serveCached[T any](ctx context.Context, s *AlphaVantageService, ticker, key string, view *cache.Typed[cachedValue[T]], group *flightGroup[T], fetch func(context.Context) (T, error)) (T, error) {
+	now := time.Now()
+	entry, cached := lookupCached(ctx, view, key)
+
+	if cached && now.Before(entry.Expiry) {
+		recordFreshness(ctx, ticker, false, entry.StoredAt)
+		return entry.Value, nil
+	}
+
+	if cached && now.Before(entry.Expiry.Add(s.staleWhileRevalidate)) {
+		go revalidate(key, group, fetch)
+		recordFreshness(ctx, ticker, true, entry.StoredAt)
+		return entry.Value, nil
+	}
+
+	result, err := group.Do(ctx, key, fetch)
+	if err != nil {
+		if cached && ctx.Err() == nil && now.Before(entry.StoredAt.Add(s.maxStale)) {
+			logrus.WithFields(logrus.Fields{
+				"key":   key,
+				"asOf":  entry.StoredAt,
+				"error": err,
+			}).Warn("Serving stale value after upstream error")
+			recordFreshness(ctx, ticker, true, entry.StoredAt)
+			return entry.Value, nil
+		}
+		return result, err
+	}
+
+	recordFreshness(ctx, ticker, false, time.Now())
+	return result, nil
+}
+
+// revalidate refreshes a stale cache entry in the background. Refreshes
+// yield upstream quota to interactive requests and share any call already
+// in flight for the key.
+func revalidate[T any](key string, group *flightGroup[T], fetch func(context.Context) (T, error)) {
+	ctx, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityBackground), revalidateTimeout)
+	defer cancel()
+
+	if _, err := group.Do(ctx, key, fetch); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":   key,
+			"error": err,
+		}).Warn("Background cache refresh failed")
+	}
+}
+
+// fetchPriceHistory returns the bars within the range and caches them under
+// cacheKey. With a history store, daily bars already held locally are
+// served from it and only the missing trailing days are requested.
+func (s *AlphaVantageService) fetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate, cacheKey string) ([]types.HistoricalPricePoint, error) {
+	if interval.Intraday() {
+		points, err := s.fetchIntradaySeries(ctx, ticker, interval)
+		if err != nil {
+			return nil, err
+		}
+		historicalData, err := filterBars(points, interval, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+
+		storeCached(ctx, s, s.histories(), cacheKey, historicalData)
+		return historicalData, nil
 	}
 
-	// Build request URL for TIME_SERIES_DAILY
+	var series history.Series
+	if s.history == nil {
+		points, err := s.fetchDailySeries(ctx, ticker, "full")
+		if err != nil {
+			return nil, err
+		}
+		series = history.Series{Ticker: ticker, Points: points, Adjusted: true}
+	} else {
+		var err error
+		series, err = s.syncHistory(ctx, ticker, toDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	historicalData := series.Range(fromDate, toDate)
+
+	// Cache the results
+	storeCached(ctx, s, s.histories(), cacheKey, historicalData)
+
+	return historicalData, nil
+}
+
+// syncHistory returns the stored series for ticker, first bringing it up to
+// date if it does not cover toDate. Tickers seen for the first time, not
+// updated for longer than a compact response spans, or stored without
+// adjusted closes are fetched in full.
+func (s *AlphaVantageService) syncHistory(ctx context.Context, ticker, toDate string) (history.Series, error) {
+	series, found, err := s.history.Load(ctx, ticker)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ticker": ticker,
+			"error":  err,
+		}).Warn("Failed to load stored history, fetching in full")
+		found = false
+	}
+	if found && !series.Adjusted {
+		found = false
+	}
+
+	now := time.Now()
+	endDate := toDate
+	if today := now.UTC().Format(history.DateLayout); endDate == "" || endDate > today {
+		endDate = today
+	}
+	if found && (endDate <= series.CoveredThrough() || now.Sub(series.FetchedAt) < s.cacheTTL) {
+		return series, nil
+	}
+
+	return s.syncFlight.Do(ctx, ticker, func(ctx context.Context) (history.Series, error) {
+		outputSize := "full"
+		if found {
+			last, err := time.Parse(history.DateLayout, series.LastDate())
+			if err == nil && now.Sub(last) < compactSpan {
+				outputSize = "compact"
+			}
+		}
+
+		points, err := s.fetchDailySeries(ctx, ticker, outputSize)
+		if err != nil {
+			return history.Series{}, err
+		}
+
+		updated := history.Series{Ticker: ticker, Points: points, FetchedAt: now, Adjusted: true}
+		if outputSize == "compact" {
+			updated.Points = history.Merge(series.Points, points)
+		}
+
+		if err := s.history.Save(ctx, updated); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"ticker": ticker,
+				"error":  err,
+			}).Warn("Failed to store history")
+		}
+		return updated, nil
+	})
+}
+
+// fetchDailySeries requests the adjusted daily series from the API, sorted
+// by date. A compact response holds the latest 100 bars, a full one the
+// entire history.
+func (s *AlphaVantageService) fetchDailySeries(ctx context.Context, ticker, outputSize string) ([]types.HistoricalPricePoint, error) {
+	avSymbol, err := alphaVantageSymbol(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request URL for TIME_SERIES_DAILY_ADJUSTED
 	params := url.Values{}
-	params.Set("function", "TIME_SERIES_DAILY")
-	params.Set("symbol", ticker)
-	params.Set("apikey", s.apiKey)
+	params.Set("function", "TIME_SERIES_DAILY_ADJUSTED")
+	params.Set("symbol", avSymbol)
+	params.Set("outputsize", outputSize)
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTimeSeries(body, "Time Series (Daily)", ticker, func(date string) (string, error) {
+		return date, nil
+	})
+}
+
+// fetchIntradaySeries requests the intraday series from the API, sorted by
+// time and stamped with RFC3339 times. Bars are requested as traded, like
+// the OHLC of daily bars.
+func (s *AlphaVantageService) fetchIntradaySeries(ctx context.Context, ticker string, interval Interval) ([]types.HistoricalPricePoint, error) {
+	avSymbol, err := alphaVantageSymbol(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("function", "TIME_SERIES_INTRADAY")
+	params.Set("symbol", avSymbol)
+	params.Set("interval", string(interval))
 	params.Set("outputsize", "full")
+	params.Set("adjusted", "false")
+
+	body, err := s.query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bars are stamped in the exchange's local time, named in the metadata
+	var meta struct {
+		MetaData struct {
+			TimeZone string `json:"6. Time Zone"`
+		} `json:"Meta Data"`
+	}
+	loc := marketLocation
+	if err := json.Unmarshal(body, &meta); err == nil && meta.MetaData.TimeZone != "" {
+		if zone, err := time.LoadLocation(meta.MetaData.TimeZone); err == nil {
+			loc = zone
+		}
+	}
+
+	key := fmt.Sprintf("Time Series (%s)", interval)
+	return parseTimeSeries(body, key, ticker, func(stamp string) (string, error) {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", stamp, loc)
+		if err != nil {
+			return "", err
+		}
+		return t.Format(time.RFC3339), nil
+	})
+}
+
+// parseTimeSeries extracts the bars under key from a time series response.
+// Bars are sorted by their upstream stamp, which stamp converts to the
+// form returned to callers.
+func parseTimeSeries(body []byte, key, ticker string, stamp func(string) (string, error)) ([]types.HistoricalPricePoint, error) {
+	var avResponse map[string]interface{}
+	if err := json.Unmarshal(body, &avResponse); err != nil {
+		return nil, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
+	}
+
+	// Extract time series data
+	timeSeries, ok := avResponse[key].(map[string]interface{})
+	if !ok {
+		return nil, apperror.New(apperror.ErrUpstreamUnavailable, "invalid response: time series data not found for ticker %s", ticker)
+	}
 
+	// Local times sort correctly as strings
+	stamps := make([]string, 0, len(timeSeries))
+	for raw := range timeSeries {
+		stamps = append(stamps, raw)
+	}
+	sort.Strings(stamps)
+
+	// Parse historical data points
+	historicalData := make([]types.HistoricalPricePoint, 0, len(stamps))
+	for _, raw := range stamps {
+		dataPoint, ok := timeSeries[raw].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		date, err := stamp(raw)
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "invalid bar time %q", raw)
+		}
+
+		open, _ := parsePrice(barField(dataPoint, "open"))
+		high, _ := parsePrice(barField(dataPoint, "high"))
+		low, _ := parsePrice(barField(dataPoint, "low"))
+		close, _ := parsePrice(barField(dataPoint, "close"))
+		volume, _ := parsePrice(barField(dataPoint, "volume"))
+		adjustedClose, _ := parsePrice(barField(dataPoint, "adjusted close"))
+		dividend, _ := parsePrice(barField(dataPoint, "dividend amount"))
+		split, _ := parsePrice(barField(dataPoint, "split coefficient"))
+
+		historicalData = append(historicalData, types.HistoricalPricePoint{
+			Date:             date,
+			Open:             open,
+			High:             high,
+			Low:              low,
+			Close:            close,
+			Volume:           int64(volume),
+			AdjustedClose:    adjustedClose,
+			DividendAmount:   dividend,
+			SplitCoefficient: split,
+		})
+	}
+
+	return historicalData, nil
+}
+
+// query performs a rate-limited Alpha Vantage API call and returns the
+// response body, converting error envelopes into AlphaVantageError
+func (s *AlphaVantageService) query(ctx context.Context, params url.Values) ([]byte, error) {
+	// Wait for an upstream slot
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	params.Set("apikey", s.apiKey)
 	reqURL := fmt.Sprintf("%s?%s", s.baseURL, params.Encode())
 
 	// Create HTTP request
@@ -208,72 +900,114 @@ func (s *AlphaVantageService) FetchPriceHistory(ctx context.Context, ticker, fro
 	// Execute request
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch history: %w", err)
+		return nil, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to call %s", params.Get("function"))
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		kind := apperror.ErrUpstreamUnavailable
+		if resp.StatusCode == http.StatusTooManyRequests {
+			kind = apperror.ErrRateLimited
+		}
+		return nil, apperror.New(kind, "API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to read response body")
 	}
 
-	var avResponse map[string]interface{}
-	if err := json.Unmarshal(body, &avResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := checkEnvelope(body); err != nil {
+		return nil, err
 	}
 
-	// Extract time series data
-	timeSeries, ok := avResponse["Time Series (Daily)"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid response: time series data not found for ticker %s", ticker)
+	return body, nil
+}
+
+// checkEnvelope detects the error bodies Alpha Vantage sends with HTTP 200
+func checkEnvelope(body []byte) error {
+	var envelope alphaVantageEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		// Let the caller report the malformed body
+		return nil
 	}
 
-	// Parse historical data points
-	var historicalData []types.HistoricalPricePoint
-	for date, data := range timeSeries {
-		dataPoint, ok := data.(map[string]interface{})
-		if !ok {
-			continue
+	switch {
+	case envelope.ErrorMessage != "":
+		lower := strings.ToLower(envelope.ErrorMessage)
+		if strings.Contains(lower, "apikey") {
+			return &AlphaVantageError{Kind: ErrInvalidAPIKey, Message: envelope.ErrorMessage}
 		}
-
-		open, _ := parsePrice(dataPoint["1. open"])
-		high, _ := parsePrice(dataPoint["2. high"])
-		low, _ := parsePrice(dataPoint["3. low"])
-		close, _ := parsePrice(dataPoint["4. close"])
-
-		// Filter by date range if specified
-		if fromDate != "" && date < fromDate {
-			continue
+		if strings.Contains(lower, "api function") && strings.Contains(lower, "does not exist") {
+			return &AlphaVantageError{Kind: ErrUnsupportedEndpoint, Message: envelope.ErrorMessage}
 		}
-		if toDate != "" && date > toDate {
-			continue
+		return &AlphaVantageError{Kind: ErrInvalidSymbol, Message: envelope.ErrorMessage}
+	case envelope.Note != "":
+		return &AlphaVantageError{Kind: ErrRateLimited, Message: envelope.Note}
+	case envelope.Information != "":
+		lower := strings.ToLower(envelope.Information)
+		if strings.Contains(lower, "premium endpoint") {
+			return &AlphaVantageError{Kind: ErrUnsupportedEndpoint, Message: envelope.Information}
 		}
-
-		historicalData = append(historicalData, types.HistoricalPricePoint{
-			Date:  date,
-			Open:  open,
-			High:  high,
-			Low:   low,
-			Close: close,
-		})
+		if strings.Contains(lower, "api key") && !strings.Contains(lower, "rate limit") {
+			return &AlphaVantageError{Kind: ErrInvalidAPIKey, Message: envelope.Information}
+		}
+		return &AlphaVantageError{Kind: ErrRateLimited, Message: envelope.Information}
 	}
 
-	// Sort by date (descending order in API, reverse to ascending)
-	for i, j := 0, len(historicalData)-1; i < j; i, j = i+1, j-1 {
-		historicalData[i], historicalData[j] = historicalData[j], historicalData[i]
+	return nil
+}
+
+// alphaVantageSuffixes are the suffixes Alpha Vantage gives the exchanges
+// it covers outside the US, by canonical suffix
+var alphaVantageSuffixes = map[string]string{
+	"TO": "TRT",
+	"V":  "TRV",
+	"L":  "LON",
+	"DE": "DEX",
+	"BO": "BSE",
+	"SS": "SHH",
+	"SZ": "SHZ",
+}
+
+// alphaVantageSymbol translates a ticker to Alpha Vantage's format, which
+// writes share classes with a hyphen (BRK-B) and uses its own exchange
+// suffixes (TSCO.LON). Crypto pairs and other exchanges are not served.
+func alphaVantageSymbol(ticker string) (string, error) {
+	sym, err := symbol.Parse(ticker)
+	if err != nil {
+		return "", err
+	}
+	if sym.Asset != symbol.AssetEquity {
+		return "", apperror.NotFound("alphavantage does not quote %s pairs such as %s", sym.Asset, ticker)
 	}
 
-	// Cache the results
-	s.setCachedHistory(cacheKey, historicalData)
+	avSymbol := sym.Base
+	if sym.Class != "" {
+		avSymbol += "-" + sym.Class
+	}
+	if sym.Exchange != "" {
+		suffix, ok := alphaVantageSuffixes[sym.Exchange]
+		if !ok {
+			return "", apperror.NotFound("alphavantage does not cover the exchange of %s", ticker)
+		}
+		avSymbol += "." + suffix
+	}
+	return avSymbol, nil
+}
 
-	return historicalData, nil
+// barField returns the named field of a bar. Fields are numbered by their
+// position, which differs between series: volume is "5. volume" in intraday
+// bars but "6. volume" in adjusted daily bars.
+func barField(bar map[string]interface{}, name string) interface{} {
+	for key, value := range bar {
+		if _, field, ok := strings.Cut(key, ". "); ok && field == name {
+			return value
+		}
+	}
+	return nil
 }
 
 func parsePrice(value interface{}) (float64, error) {
@@ -289,129 +1023,106 @@ func parsePrice(value interface{}) (float64, error) {
 	}
 }
 
-// getCachedHistory retrieves cached historical data
-func (s *AlphaVantageService) getCachedHistory(key string) ([]types.HistoricalPricePoint, bool) {
-	s.cacheMutex.RLock()
-	defer s.cacheMutex.RUnlock()
-
-	entry, exists := s.cache[key]
-	if !exists {
-		return nil, false
-	}
-
-	if time.Now().After(entry.expiry) {
-		return nil, false
-	}
-
-	return entry.history, true
+// prices is the cache view holding latest prices by ticker
+func (s *AlphaVantageService) prices() *cache.Typed[cachedValue[float64]] {
+	return cache.NewTyped[cachedValue[float64]](s.cache, "price:")
 }
 
-// setCachedHistory stores historical data in cache
-func (s *AlphaVantageService) setCachedHistory(key string, history []types.HistoricalPricePoint) {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
-
-	// Evict expired entries first
-	s.evictExpired()
-
-	// If still at capacity, evict oldest entries
-	if len(s.cache) >= s.maxCacheSize {
-		s.evictOldest()
-	}
-
-	s.cache[key] = cacheEntry{
-		history: history,
-		expiry:  time.Now().Add(s.cacheTTL),
-	}
+// quotes is the cache view holding latest quotes by ticker
+func (s *AlphaVantageService) quotes() *cache.Typed[cachedValue[types.Quote]] {
+	return cache.NewTyped[cachedValue[types.Quote]](s.cache, "quote:")
 }
 
-// getCachedPrice retrieves a price from cache if it's still valid
-func (s *AlphaVantageService) getCachedPrice(ticker string) (float64, bool) {
-	s.cacheMutex.RLock()
-	defer s.cacheMutex.RUnlock()
-
-	entry, exists := s.cache[ticker]
-	if !exists {
-		return 0, false
-	}
-
-	if time.Now().After(entry.expiry) {
-		return 0, false
-	}
-
-	return entry.price, true
+// symbolSearches is the cache view holding search results by query
+func (s *AlphaVantageService) symbolSearches() *cache.Typed[cachedValue[[]types.SymbolInfo]] {
+	return cache.NewTyped[cachedValue[[]types.SymbolInfo]](s.cache, "symbol-search:")
 }
 
-// setCachedPrice stores a price in cache with TTL
-func (s *AlphaVantageService) setCachedPrice(ticker string, price float64) {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
+// symbols is the cache view holding symbol metadata by ticker
+func (s *AlphaVantageService) symbols() *cache.Typed[cachedValue[types.SymbolInfo]] {
+	return cache.NewTyped[cachedValue[types.SymbolInfo]](s.cache, "symbol:")
+}
 
-	// Evict expired entries first
-	s.evictExpired()
+// rates is the cache view holding exchange rates by currency pair
+func (s *AlphaVantageService) rates() *cache.Typed[cachedValue[float64]] {
+	return cache.NewTyped[cachedValue[float64]](s.cache, "fx:")
+}
 
-	// If still at capacity, evict oldest entries
-	if len(s.cache) >= s.maxCacheSize {
-		s.evictOldest()
-	}
+// rateHistories is the cache view holding daily FX series by currency pair
+func (s *AlphaVantageService) rateHistories() *cache.Typed[cachedValue[map[string]float64]] {
+	return cache.NewTyped[cachedValue[map[string]float64]](s.cache, "fx-history:")
+}
 
-	s.cache[ticker] = cacheEntry{
-		price:  price,
-		expiry: time.Now().Add(s.cacheTTL),
-	}
+// histories is the cache view holding daily series by ticker and date range
+func (s *AlphaVantageService) histories() *cache.Typed[cachedValue[[]types.HistoricalPricePoint]] {
+	return cache.NewTyped[cachedValue[[]types.HistoricalPricePoint]](s.cache, "history:")
 }
 
-// evictExpired removes all expired entries from the cache
-func (s *AlphaVantageService) evictExpired() {
-	now := time.Now()
-	for ticker, entry := range s.cache {
-		if now.After(entry.expiry) {
-			delete(s.cache, ticker)
-		}
+// lookupCached returns the entry for key, fresh or stale. Cache failures
+// are logged and treated as misses so a broken backend only costs quota.
+func lookupCached[T any](ctx context.Context, view *cache.Typed[cachedValue[T]], key string) (cachedValue[T], bool) {
+	entry, found, err := view.Get(ctx, key)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":   key,
+			"error": err,
+		}).Warn("Cache lookup failed")
+		return cachedValue[T]{}, false
 	}
+	return entry, found
 }
 
-// evictOldest removes approximately 10% of oldest entries when cache is full
-func (s *AlphaVantageService) evictOldest() {
-	if len(s.cache) == 0 {
-		return
-	}
+// storeCached caches value under key, keeping it for as long as it may
+// still be served stale
+func storeCached[T any](ctx context.Context, s *AlphaVantageService, view *cache.Typed[cachedValue[T]], key string, value T) {
+	storeCachedFor(ctx, s, view, key, value, s.cacheTTL)
+}
 
-	// Collect all entries with their expiry times
-	type tickerExpiry struct {
-		ticker string
-		expiry time.Time
+// storeCachedFor caches value under key as fresh for ttl rather than the
+// service's cache TTL
+func storeCachedFor[T any](ctx context.Context, s *AlphaVantageService, view *cache.Typed[cachedValue[T]], key string, value T, ttl time.Duration) {
+	now := time.Now()
+	entry := cachedValue[T]{
+		Value:    value,
+		StoredAt: now,
+		Expiry:   now.Add(ttl),
 	}
 
-	entries := make([]tickerExpiry, 0, len(s.cache))
-	for ticker, entry := range s.cache {
-		entries = append(entries, tickerExpiry{ticker, entry.expiry})
+	if err := view.Set(ctx, key, entry, s.retention(ttl)); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":   key,
+			"error": err,
+		}).Warn("Cache store failed")
 	}
+}
 
-	// Sort by expiry (oldest first)
-	for i := 0; i < len(entries); i++ {
-		for j := i + 1; j < len(entries); j++ {
-			if entries[i].expiry.After(entries[j].expiry) {
-				entries[i], entries[j] = entries[j], entries[i]
-			}
-		}
+// retention returns how long an entry fresh for ttl stays useful, even as a
+// stale value
+func (s *AlphaVantageService) retention(ttl time.Duration) time.Duration {
+	retain := ttl + s.staleWhileRevalidate
+	if s.maxStale > retain {
+		retain = s.maxStale
 	}
+	return retain
+}
 
-	// Remove approximately 10% of entries
-	numToRemove := len(entries) / 10
-	if numToRemove < 1 {
-		numToRemove = 1
+// getCachedPrice retrieves a price from cache if it's still valid
+func (s *AlphaVantageService) getCachedPrice(ticker string) (float64, bool) {
+	entry, found := lookupCached(context.Background(), s.prices(), ticker)
+	if !found || time.Now().After(entry.Expiry) {
+		return 0, false
 	}
+	return entry.Value, true
+}
 
-	for i := 0; i < numToRemove; i++ {
-		delete(s.cache, entries[i].ticker)
-	}
+// setCachedPrice stores a price in cache with TTL
+func (s *AlphaVantageService) setCachedPrice(ticker string, price float64) {
+	storeCached(context.Background(), s, s.prices(), ticker, price)
 }
 
 // ClearCache clears all cached prices
 func (s *AlphaVantageService) ClearCache() {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
-
-	s.cache = make(map[string]cacheEntry)
-}
\ No newline at end of file
+	if err := s.cache.Clear(context.Background()); err != nil {
+		logrus.WithError(err).Warn("Failed to clear cache")
+	}
+}
@@ -2,10 +2,22 @@ package service
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/cache"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
 )
 
 func TestNewAlphaVantageService(t *testing.T) {
@@ -66,7 +78,7 @@ func TestAlphaVantageService_FetchPrice(t *testing.T) {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		cache:    make(map[string]cacheEntry),
+		cache:    cache.NewLRU(100),
 		cacheTTL: 5 * time.Minute,
 	}
 
@@ -149,7 +161,7 @@ func TestAlphaVantageService_APIError(t *testing.T) {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		cache:    make(map[string]cacheEntry),
+		cache:    cache.NewLRU(100),
 		cacheTTL: 5 * time.Minute,
 	}
 
@@ -174,7 +186,7 @@ func TestAlphaVantageService_InvalidResponse(t *testing.T) {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		cache:    make(map[string]cacheEntry),
+		cache:    cache.NewLRU(100),
 		cacheTTL: 5 * time.Minute,
 	}
 
@@ -200,4 +212,726 @@ func TestAlphaVantageService_EnvironmentVariable(t *testing.T) {
 	if svc.apiKey != "demo" {
 		t.Errorf("Expected API key to default to 'demo', got '%s'", svc.apiKey)
 	}
-}
\ No newline at end of file
+}
+
+func TestAlphaVantageService_ErrorEnvelopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantKind  error
+		priceOnly bool
+	}{
+		{
+			name:     "Throttle note",
+			body:     `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute and 500 calls per day."}`,
+			wantKind: ErrRateLimited,
+		},
+		{
+			name:     "Daily limit information",
+			body:     `{"Information": "We have detected your API key as demo and our standard API rate limit is 25 requests per day."}`,
+			wantKind: ErrRateLimited,
+		},
+		{
+			name:     "Invalid symbol",
+			body:     `{"Error Message": "Invalid API call. Please retry or visit the documentation (https://www.alphavantage.co/documentation/) for TIME_SERIES_DAILY."}`,
+			wantKind: ErrInvalidSymbol,
+		},
+		{
+			name:     "Invalid API key",
+			body:     `{"Error Message": "the parameter apikey is invalid or missing. Please claim your free API key on (https://www.alphavantage.co/support/#api-key)."}`,
+			wantKind: ErrInvalidAPIKey,
+		},
+		{
+			name:     "Premium endpoint",
+			body:     `{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint. You may subscribe to any of the premium plans at https://www.alphavantage.co/premium/ to instantly unlock all premium endpoints"}`,
+			wantKind: ErrUnsupportedEndpoint,
+		},
+		{
+			name:     "Unknown function",
+			body:     `{"Error Message": "This API function (TIME_SERIES_DAILY) does not exist."}`,
+			wantKind: ErrUnsupportedEndpoint,
+		},
+		{
+			name:      "Empty quote",
+			body:      `{"Global Quote": {}}`,
+			wantKind:  ErrInvalidSymbol,
+			priceOnly: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			svc := &AlphaVantageService{
+				apiKey:  "test-key",
+				baseURL: server.URL,
+				httpClient: &http.Client{
+					Timeout: 10 * time.Second,
+				},
+				cache:    cache.NewLRU(100),
+				cacheTTL: 5 * time.Minute,
+			}
+
+			_, err := svc.FetchPrice(context.Background(), "AAPL")
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("FetchPrice() error = %v, want %v", err, tt.wantKind)
+			}
+
+			if tt.priceOnly {
+				return
+			}
+			_, err = svc.FetchPriceHistory(context.Background(), "AAPL", IntervalDaily, "", "")
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("FetchPriceHistory() error = %v, want %v", err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestCheckEnvelope_PremiumEndpoint(t *testing.T) {
+	err := checkEnvelope([]byte(`{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint. You may subscribe to any of the premium plans at https://www.alphavantage.co/premium/ to instantly unlock all premium endpoints"}`))
+	if errors.Is(err, apperror.ErrRateLimited) {
+		t.Errorf("checkEnvelope() error = %v, want it not to be retried as rate limited", err)
+	}
+	if status := apperror.HTTPStatus(err); status != http.StatusBadGateway {
+		t.Errorf("HTTPStatus() = %d, want %d", status, http.StatusBadGateway)
+	}
+}
+
+func TestAlphaVantageService_CoalescesConcurrentFetches(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Global Quote": {"01. symbol": "IBM", "05. price": "190.0000"}}`))
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		cache:       cache.NewLRU(100),
+		cacheTTL:    5 * time.Minute,
+		priceFlight: flightGroup[float64]{metric: "test_price"},
+	}
+
+	before := coalescedCount("test_price_deduplicated")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			price, err := svc.FetchPrice(context.Background(), "IBM")
+			if err != nil || price != 190.0 {
+				t.Errorf("FetchPrice() = %v, %v; want 190.0", price, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Upstream calls = %d, want 1", got)
+	}
+	if got := coalescedCount("test_price_deduplicated") - before; got != 49 {
+		t.Errorf("Deduplicated calls = %d, want 49", got)
+	}
+}
+
+func TestAlphaVantageService_QuotaExhaustedBeforeDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Global Quote": {"01. symbol": %q, "05. price": "190.0000"}}`, r.URL.Query().Get("symbol"))
+	}))
+	defer server.Close()
+
+	svc := NewAlphaVantageServiceWithOptions(ProviderOptions{
+		AlphaVantage: &AlphaVantageOptions{RequestsPerMinute: 3, RequestsPerDay: 1000},
+	})
+	svc.baseURL = server.URL
+
+	// Drain the per-minute budget
+	for _, ticker := range []string{"IBM", "MSFT", "GOOGL"} {
+		if _, err := svc.FetchPrice(context.Background(), ticker); err != nil {
+			t.Fatalf("FetchPrice(%s) error = %v", ticker, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := svc.FetchPrice(ctx, "AAPL")
+	var quotaErr *QuotaExhaustedError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("FetchPrice() error = %v, want QuotaExhaustedError", err)
+	}
+	if quotaErr.RetryAfter <= 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want the time until the next slot", quotaErr.RetryAfter)
+	}
+	if status := apperror.HTTPStatus(err); status != http.StatusTooManyRequests {
+		t.Errorf("HTTPStatus() = %d, want %d", status, http.StatusTooManyRequests)
+	}
+}
+
+func coalescedCount(key string) int64 {
+	v, ok := coalesceMetrics.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+func TestAlphaVantageService_StaleWhileRevalidate(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Global Quote": {"01. symbol": "IBM", "05. price": "%d.0000"}}`, 99+n)
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:               "test-key",
+		baseURL:              server.URL,
+		httpClient:           &http.Client{Timeout: 10 * time.Second},
+		cache:                cache.NewLRU(100),
+		cacheTTL:             50 * time.Millisecond,
+		staleWhileRevalidate: time.Minute,
+		maxStale:             time.Hour,
+	}
+
+	if price, err := svc.FetchPrice(context.Background(), "IBM"); err != nil || price != 100.0 {
+		t.Fatalf("FetchPrice() = %v, %v; want 100.0", price, err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	ctx, meta := WithResponseMeta(context.Background())
+	price, err := svc.FetchPrice(ctx, "IBM")
+	if err != nil || price != 100.0 {
+		t.Fatalf("FetchPrice() = %v, %v; want stale 100.0", price, err)
+	}
+	if tm := meta.Ticker("IBM"); !tm.Stale || tm.AsOf.IsZero() {
+		t.Errorf("Ticker() = %+v, want stale with as-of time", tm)
+	}
+
+	// The background refresh replaces the stale value
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, fresh := svc.getCachedPrice("IBM"); fresh {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, meta = WithResponseMeta(context.Background())
+	price, err = svc.FetchPrice(ctx, "IBM")
+	if err != nil || price != 101.0 {
+		t.Errorf("FetchPrice() after refresh = %v, %v; want 101.0", price, err)
+	}
+	if meta.Ticker("IBM").Stale {
+		t.Error("Expected refreshed value to be fresh")
+	}
+}
+
+func TestAlphaVantageService_ServeStaleOnError(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxStale  time.Duration
+		wantStale bool
+	}{
+		{name: "Within max stale", maxStale: time.Hour, wantStale: true},
+		{name: "Beyond max stale", maxStale: 0, wantStale: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if hits.Add(1) > 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(`{"Global Quote": {"01. symbol": "IBM", "05. price": "100.0000"}}`))
+			}))
+			defer server.Close()
+
+			svc := &AlphaVantageService{
+				apiKey:     "test-key",
+				baseURL:    server.URL,
+				httpClient: &http.Client{Timeout: 10 * time.Second},
+				cache:      cache.NewLRU(100),
+				cacheTTL:   10 * time.Millisecond,
+				maxStale:   tt.maxStale,
+			}
+
+			if _, err := svc.FetchPrice(context.Background(), "IBM"); err != nil {
+				t.Fatalf("FetchPrice() error = %v", err)
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			ctx, meta := WithResponseMeta(context.Background())
+			price, err := svc.FetchPrice(ctx, "IBM")
+			if !tt.wantStale {
+				if err == nil {
+					t.Errorf("FetchPrice() = %v, want error", price)
+				}
+				return
+			}
+			if err != nil || price != 100.0 {
+				t.Fatalf("FetchPrice() = %v, %v; want stale 100.0", price, err)
+			}
+			if !meta.Ticker("IBM").Stale {
+				t.Error("Expected value served after an upstream error to be stale")
+			}
+		})
+	}
+}
+
+func TestAlphaVantageService_SharedCache(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"Global Quote": {"01. symbol": "IBM", "05. price": "100.0000"}}`))
+	}))
+	defer server.Close()
+
+	// Two replicas pointed at the same backend
+	shared := cache.NewLRU(100)
+	replicas := make([]*AlphaVantageService, 2)
+	for i := range replicas {
+		replicas[i] = &AlphaVantageService{
+			apiKey:     "test-key",
+			baseURL:    server.URL,
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+			cache:      shared,
+			cacheTTL:   time.Minute,
+		}
+	}
+
+	for _, svc := range replicas {
+		if price, err := svc.FetchPrice(context.Background(), "IBM"); err != nil || price != 100.0 {
+			t.Fatalf("FetchPrice() = %v, %v; want 100.0", price, err)
+		}
+	}
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Upstream calls = %d, want 1", got)
+	}
+}
+
+func TestAlphaVantageService_HistoryBackfill(t *testing.T) {
+	today := time.Now().UTC()
+	day := func(offset int) string {
+		return today.AddDate(0, 0, offset).Format(history.DateLayout)
+	}
+
+	var mu sync.Mutex
+	var outputSizes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		outputSizes = append(outputSizes, r.URL.Query().Get("outputsize"))
+		mu.Unlock()
+
+		first := -30
+		if r.URL.Query().Get("outputsize") == "compact" {
+			first = -5
+		}
+		bars := make([]string, 0)
+		for offset := first; offset <= 0; offset++ {
+			bars = append(bars, fmt.Sprintf(`"%s": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "%d"}`, day(offset), 100+offset))
+		}
+		fmt.Fprintf(w, `{"Time Series (Daily)": {%s}}`, strings.Join(bars, ","))
+	}))
+	defer server.Close()
+
+	store, err := history.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	newService := func() *AlphaVantageService {
+		return &AlphaVantageService{
+			apiKey:     "test-key",
+			baseURL:    server.URL,
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+			cache:      cache.NewLRU(100),
+			cacheTTL:   5 * time.Minute,
+			history:    store,
+		}
+	}
+	calls := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), outputSizes...)
+	}
+
+	// The first request downloads the full history, sorted oldest first
+	points, err := newService().FetchPriceHistory(context.Background(), "IBM", IntervalDaily, day(-10), day(-8))
+	if err != nil {
+		t.Fatalf("FetchPriceHistory() error = %v", err)
+	}
+	if len(points) != 3 || points[0].Date != day(-10) || points[2].Date != day(-8) {
+		t.Errorf("FetchPriceHistory() = %+v, want 3 bars from %s", points, day(-10))
+	}
+
+	// After a restart, history already held costs no quota
+	points, err = newService().FetchPriceHistory(context.Background(), "IBM", IntervalDaily, day(-20), day(-1))
+	if err != nil || len(points) != 20 {
+		t.Fatalf("FetchPriceHistory() = %d bars, %v; want 20", len(points), err)
+	}
+	if got := calls(); len(got) != 1 || got[0] != "full" {
+		t.Fatalf("Upstream calls = %v, want [full]", got)
+	}
+
+	// Once the store falls behind, only the trailing days are fetched
+	series, _, _ := store.Load(context.Background(), "IBM")
+	series.Points = series.Points[:len(series.Points)-3]
+	series.FetchedAt = today.AddDate(0, 0, -3)
+	store.Save(context.Background(), series)
+
+	points, err = newService().FetchPriceHistory(context.Background(), "IBM", IntervalDaily, day(-30), "")
+	if err != nil || len(points) != 31 {
+		t.Fatalf("FetchPriceHistory() = %d bars, %v; want 31", len(points), err)
+	}
+	if points[30].Date != day(0) {
+		t.Errorf("Last bar = %s, want %s", points[30].Date, day(0))
+	}
+	if got := calls(); len(got) != 2 || got[1] != "compact" {
+		t.Errorf("Upstream calls = %v, want [full compact]", got)
+	}
+}
+
+func TestAlphaVantageService_IntradayHistory(t *testing.T) {
+	var query atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query.Store(r.URL.Query())
+		w.Write([]byte(`{
+			"Meta Data": {"6. Time Zone": "US/Eastern"},
+			"Time Series (5min)": {
+				"2024-01-03 09:35:00": {"1. open": "2", "2. high": "2", "3. low": "2", "4. close": "2", "5. volume": "1500"},
+				"2024-01-03 09:30:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1"},
+				"2024-01-02 15:55:00": {"1. open": "0", "2. high": "0", "3. low": "0", "4. close": "0"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      cache.NewLRU(100),
+		cacheTTL:   5 * time.Minute,
+	}
+
+	points, err := svc.FetchPriceHistory(context.Background(), "IBM", Interval5Min, "2024-01-03", "")
+	if err != nil {
+		t.Fatalf("FetchPriceHistory() error = %v", err)
+	}
+
+	params := query.Load().(url.Values)
+	if params.Get("function") != "TIME_SERIES_INTRADAY" || params.Get("interval") != "5min" || params.Get("adjusted") != "false" {
+		t.Errorf("Request params = %v, want raw TIME_SERIES_INTRADAY at 5min", params)
+	}
+
+	want := []string{"2024-01-03T09:30:00-05:00", "2024-01-03T09:35:00-05:00"}
+	if len(points) != len(want) {
+		t.Fatalf("FetchPriceHistory() = %+v, want bars at %v", points, want)
+	}
+	for i, date := range want {
+		if points[i].Date != date {
+			t.Errorf("FetchPriceHistory()[%d].Date = %s, want %s", i, points[i].Date, date)
+		}
+	}
+	if points[1].Volume != 1500 {
+		t.Errorf("FetchPriceHistory()[1].Volume = %d, want 1500", points[1].Volume)
+	}
+
+	// Daily and intraday ranges are cached apart
+	if _, found := lookupCached(context.Background(), svc.histories(), "IBM_5min_2024-01-03_"); !found {
+		t.Error("Expected intraday bars to be cached under their interval")
+	}
+}
+
+func TestAlphaVantageService_AdjustedDailySeries(t *testing.T) {
+	var mu sync.Mutex
+	var functions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		functions = append(functions, r.URL.Query().Get("function")+" "+r.URL.Query().Get("outputsize"))
+		mu.Unlock()
+		w.Write([]byte(`{
+			"Time Series (Daily)": {
+				"2020-08-31": {"1. open": "127.58", "2. high": "131.0", "3. low": "126.0", "4. close": "129.04",
+					"5. adjusted close": "127.15", "6. volume": "225702700", "7. dividend amount": "0.0000", "8. split coefficient": "4.0"},
+				"2020-08-28": {"1. open": "504.05", "2. high": "505.77", "3. low": "498.31", "4. close": "499.23",
+					"5. adjusted close": "122.98", "6. volume": "46907479", "7. dividend amount": "0.0000", "8. split coefficient": "1.0"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	store, err := history.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	// History stored before adjusted closes were tracked is replaced
+	store.Save(context.Background(), history.Series{
+		Ticker:    "AAPL",
+		Points:    []types.HistoricalPricePoint{{Date: "2020-08-28", Close: 499.23}},
+		FetchedAt: time.Now(),
+	})
+
+	svc := &AlphaVantageService{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      cache.NewLRU(100),
+		cacheTTL:   5 * time.Minute,
+		history:    store,
+	}
+
+	points, err := svc.FetchPriceHistory(context.Background(), "AAPL", IntervalDaily, "2020-08-28", "2020-08-31")
+	if err != nil {
+		t.Fatalf("FetchPriceHistory() error = %v", err)
+	}
+	mu.Lock()
+	if len(functions) != 1 || functions[0] != "TIME_SERIES_DAILY_ADJUSTED full" {
+		t.Errorf("Upstream calls = %v, want one full TIME_SERIES_DAILY_ADJUSTED", functions)
+	}
+	mu.Unlock()
+
+	want := []types.HistoricalPricePoint{
+		{Date: "2020-08-28", Open: 504.05, High: 505.77, Low: 498.31, Close: 499.23, Volume: 46907479, AdjustedClose: 122.98, SplitCoefficient: 1},
+		{Date: "2020-08-31", Open: 127.58, High: 131, Low: 126, Close: 129.04, Volume: 225702700, AdjustedClose: 127.15, SplitCoefficient: 4},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("FetchPriceHistory() = %+v, want %+v", points, want)
+	}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("FetchPriceHistory()[%d] = %+v, want %+v", i, points[i], want[i])
+		}
+	}
+
+	series, _, _ := store.Load(context.Background(), "AAPL")
+	if !series.Adjusted || len(series.Points) != 2 {
+		t.Errorf("Stored series = %+v, want the adjusted history", series)
+	}
+}
+
+func TestAlphaVantageService_FetchQuote(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"Global Quote": {
+			"01. symbol": "IBM", "02. open": "189.1000", "03. high": "191.5000", "04. low": "188.7500",
+			"05. price": "190.2500", "06. volume": "4213008", "07. latest trading day": "2024-01-05",
+			"08. previous close": "188.9000", "09. change": "1.3500", "10. change percent": "0.7147%"
+		}}`))
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      cache.NewLRU(100),
+		cacheTTL:   5 * time.Minute,
+	}
+
+	quote, err := svc.FetchQuote(context.Background(), "IBM")
+	if err != nil {
+		t.Fatalf("FetchQuote() error = %v", err)
+	}
+	want := types.Quote{
+		Ticker: "IBM", Open: 189.1, High: 191.5, Low: 188.75, Price: 190.25, Volume: 4213008,
+		LatestTradingDay: "2024-01-05", PreviousClose: 188.9, Change: 1.35, ChangePercent: 0.7147,
+	}
+	if quote != want {
+		t.Errorf("FetchQuote() = %+v, want %+v", quote, want)
+	}
+
+	// The quote call also caches the price
+	price, err := svc.FetchPrice(context.Background(), "IBM")
+	if err != nil || price != 190.25 {
+		t.Errorf("FetchPrice() = %v, %v; want 190.25", price, err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Upstream calls = %d, want 1", got)
+	}
+}
+
+func TestAlphaVantageService_Symbols(t *testing.T) {
+	var mu sync.Mutex
+	var functions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		functions = append(functions, r.URL.Query().Get("function"))
+		mu.Unlock()
+
+		switch r.URL.Query().Get("function") {
+		case "SYMBOL_SEARCH":
+			w.Write([]byte(`{"bestMatches": [
+				{"1. symbol": "TSCO.LON", "2. name": "Tesco PLC", "3. type": "Equity", "4. region": "United Kingdom",
+					"5. marketOpen": "08:00", "6. marketClose": "16:30", "7. timezone": "UTC+01", "8. currency": "GBX", "9. matchScore": "0.7273"},
+				{"1. symbol": "TSCO", "2. name": "Tractor Supply Co", "3. type": "Equity", "4. region": "United States",
+					"5. marketOpen": "09:30", "6. marketClose": "16:00", "7. timezone": "UTC-04", "8. currency": "USD", "9. matchScore": "1.0000"}
+			]}`))
+		case "OVERVIEW":
+			w.Write([]byte(`{"Symbol": "TSCO", "AssetType": "Common Stock", "Exchange": "NASDAQ", "Currency": "USD"}`))
+		}
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      cache.NewLRU(100),
+		cacheTTL:   5 * time.Minute,
+	}
+
+	matches, err := svc.SearchSymbols(context.Background(), "TSCO")
+	if err != nil {
+		t.Fatalf("SearchSymbols() error = %v", err)
+	}
+	want := []types.SymbolInfo{
+		{Symbol: "TSCO", Name: "Tractor Supply Co", Type: "Equity", Region: "United States", Currency: "USD", MatchScore: 1},
+		{Symbol: "TSCO.L", Name: "Tesco PLC", Exchange: "LSE", Type: "Equity", Region: "United Kingdom", Currency: "GBX", MatchScore: 0.7273},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("SearchSymbols() = %+v, want %+v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("SearchSymbols()[%d] = %+v, want %+v", i, matches[i], want[i])
+		}
+	}
+
+	// The lookup reuses the cached search and fills in the exchange
+	info, err := svc.FetchSymbol(context.Background(), "tsco")
+	if err != nil {
+		t.Fatalf("FetchSymbol() error = %v", err)
+	}
+	wantInfo := types.SymbolInfo{Symbol: "TSCO", Name: "Tractor Supply Co", Exchange: "NASDAQ", Type: "Equity", Region: "United States", Currency: "USD"}
+	if info != wantInfo {
+		t.Errorf("FetchSymbol() = %+v, want %+v", info, wantInfo)
+	}
+
+	// Metadata outlives the price cache TTL
+	entry, found := lookupCached(context.Background(), svc.symbols(), "TSCO")
+	if !found || entry.Expiry.Sub(entry.StoredAt) != symbolCacheTTL {
+		t.Errorf("Cached metadata = %+v, %v; want fresh for %v", entry, found, symbolCacheTTL)
+	}
+	svc.FetchSymbol(context.Background(), "TSCO")
+
+	mu.Lock()
+	if len(functions) != 2 || functions[0] != "SYMBOL_SEARCH" || functions[1] != "OVERVIEW" {
+		t.Errorf("Upstream calls = %v, want [SYMBOL_SEARCH OVERVIEW]", functions)
+	}
+	mu.Unlock()
+
+	if _, err := svc.FetchSymbol(context.Background(), "TSC"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Errorf("FetchSymbol() error = %v, want not found", err)
+	}
+}
+
+func TestAlphaVantageSymbol(t *testing.T) {
+	tests := []struct {
+		ticker  string
+		want    string
+		wantErr error
+	}{
+		{ticker: "IBM", want: "IBM"},
+		{ticker: "BRK.B", want: "BRK-B"},
+		{ticker: "TSCO.L", want: "TSCO.LON"},
+		{ticker: "SHOP.TO", want: "SHOP.TRT"},
+		{ticker: "7203.T", wantErr: apperror.ErrNotFound},
+		{ticker: "BTC-USD", wantErr: apperror.ErrNotFound},
+		{ticker: "NOT A SYMBOL", wantErr: apperror.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		got, err := alphaVantageSymbol(tt.ticker)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("alphaVantageSymbol(%q) error = %v, want %v", tt.ticker, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("alphaVantageSymbol(%q) = %q, %v; want %q", tt.ticker, got, err, tt.want)
+		}
+	}
+}
+
+func TestAlphaVantageService_ExchangeRates(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		query := r.URL.Query()
+		switch query.Get("function") {
+		case "CURRENCY_EXCHANGE_RATE":
+			if query.Get("from_currency") != "USD" || query.Get("to_currency") != "EUR" {
+				t.Errorf("unexpected pair %s/%s", query.Get("from_currency"), query.Get("to_currency"))
+			}
+			w.Write([]byte(`{"Realtime Currency Exchange Rate": {
+				"1. From_Currency Code": "USD", "2. From_Currency Name": "United States Dollar",
+				"3. To_Currency Code": "EUR", "4. To_Currency Name": "Euro",
+				"5. Exchange Rate": "0.91620000", "6. Last Refreshed": "2024-01-05 21:55:01",
+				"7. Time Zone": "UTC", "8. Bid Price": "0.91610000", "9. Ask Price": "0.91630000"
+			}}`))
+		case "FX_DAILY":
+			if query.Get("from_symbol") != "USD" || query.Get("to_symbol") != "EUR" {
+				t.Errorf("unexpected pair %s/%s", query.Get("from_symbol"), query.Get("to_symbol"))
+			}
+			w.Write([]byte(`{"Meta Data": {"1. Information": "Forex Daily Prices (open, high, low, close)"},
+				"Time Series FX (Daily)": {
+					"2024-01-05": {"1. open": "0.9150", "2. high": "0.9190", "3. low": "0.9120", "4. close": "0.9155"},
+					"2024-01-04": {"1. open": "0.9140", "2. high": "0.9160", "3. low": "0.9110", "4. close": "0.9148"},
+					"2024-01-03": {"1. open": "0.9100", "2. high": "0.9170", "3. low": "0.9090", "4. close": "0.9152"}
+				}}`))
+		}
+	}))
+	defer server.Close()
+
+	svc := &AlphaVantageService{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      cache.NewLRU(100),
+		cacheTTL:   5 * time.Minute,
+	}
+
+	rate, err := svc.FetchRate(context.Background(), "USD", "EUR")
+	if err != nil || rate != 0.9162 {
+		t.Errorf("FetchRate() = %v, %v; want 0.9162", rate, err)
+	}
+
+	rates, err := svc.FetchRateHistory(context.Background(), "USD", "EUR", "2024-01-04", "2024-01-05")
+	if err != nil {
+		t.Fatalf("FetchRateHistory() error = %v", err)
+	}
+	want := map[string]float64{"2024-01-04": 0.9148, "2024-01-05": 0.9155}
+	if len(rates) != len(want) || rates["2024-01-04"] != want["2024-01-04"] || rates["2024-01-05"] != want["2024-01-05"] {
+		t.Errorf("FetchRateHistory() = %v, want %v", rates, want)
+	}
+
+	// Other ranges are served from the cached series
+	if rates, err := svc.FetchRateHistory(context.Background(), "USD", "EUR", "2024-01-01", "2024-01-03"); err != nil || len(rates) != 1 {
+		t.Errorf("FetchRateHistory() = %v, %v; want one rate", rates, err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Upstream calls = %d, want 2", got)
+	}
+}
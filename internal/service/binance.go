@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+const (
+	defaultBinanceBaseURL = "https://api.binance.com"
+
+	// binanceBridge is the currency pairs without a market of their own are
+	// priced through. USD pairs are also served from its markets.
+	binanceBridge = "USDT"
+
+	// Binance's error code for a market that does not exist
+	binanceInvalidSymbol = -1121
+
+	// Klines come in pages of at most binanceKlineLimit bars. Longer ranges
+	// are cut off after binanceMaxKlinePages pages.
+	binanceKlineLimit    = 1000
+	binanceMaxKlinePages = 10
+)
+
+// errUnknownMarket reports a pair the exchange has no market for
+var errUnknownMarket = errors.New("unknown market")
+
+// binanceIntervals maps history intervals to kline intervals
+var binanceIntervals = map[Interval]string{
+	IntervalDaily: "1d",
+	Interval1Min:  "1m",
+	Interval5Min:  "5m",
+	Interval15Min: "15m",
+	Interval30Min: "30m",
+	Interval60Min: "1h",
+}
+
+// BinanceService implements crypto pair prices against a Binance-style REST
+// API. Equity symbols are answered with not found so that the registry
+// moves on to the next provider.
+type BinanceService struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// binanceError is the body Binance sends with non-200 responses
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// binanceTicker is the 24 hour rolling window statistics of a market
+type binanceTicker struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	PrevClosePrice     string `json:"prevClosePrice"`
+	LastPrice          string `json:"lastPrice"`
+	OpenPrice          string `json:"openPrice"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	Volume             string `json:"volume"`
+	CloseTime          int64  `json:"closeTime"`
+}
+
+// NewBinanceService creates a crypto provider for the Binance-compatible
+// API at baseURL, or Binance itself when baseURL is empty
+func NewBinanceService(baseURL string) *BinanceService {
+	if baseURL == "" {
+		baseURL = defaultBinanceBaseURL
+	}
+
+	return &BinanceService{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// FetchPrice retrieves the last traded price of a crypto pair
+func (s *BinanceService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	sym, err := cryptoSymbol(ticker)
+	if err != nil {
+		return 0, err
+	}
+	return s.pairPrice(ctx, binanceCurrency(sym.Base), binanceCurrency(sym.Quote))
+}
+
+// FetchPrices retrieves the prices of several pairs, skipping those that fail
+func (s *BinanceService) FetchPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
+	results := make(map[string]float64, len(tickers))
+	var errs []error
+
+	for _, ticker := range tickers {
+		price, err := s.FetchPrice(ctx, ticker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[ticker] = price
+	}
+
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to fetch prices for any ticker: %w", errors.Join(errs...))
+	}
+	return results, nil
+}
+
+// FetchQuote retrieves the 24 hour statistics of a pair. Pairs priced
+// through the bridge currency are converted at the current rate.
+func (s *BinanceService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	sym, err := cryptoSymbol(ticker)
+	if err != nil {
+		return types.Quote{}, err
+	}
+	base, quoteCurrency := binanceCurrency(sym.Base), binanceCurrency(sym.Quote)
+
+	var stats binanceTicker
+	params := url.Values{"symbol": {base + quoteCurrency}}
+	err = s.get(ctx, "/api/v3/ticker/24hr", params, &stats)
+	rate := 1.0
+	if errors.Is(err, errUnknownMarket) && base != binanceBridge && quoteCurrency != binanceBridge {
+		if rate, err = s.bridgeRate(ctx, quoteCurrency); err != nil {
+			return types.Quote{}, err
+		}
+		params.Set("symbol", base+binanceBridge)
+		err = s.get(ctx, "/api/v3/ticker/24hr", params, &stats)
+	}
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	price, err := parsePrice(stats.LastPrice)
+	if err != nil {
+		return types.Quote{}, apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse price")
+	}
+	open, _ := parsePrice(stats.OpenPrice)
+	high, _ := parsePrice(stats.HighPrice)
+	low, _ := parsePrice(stats.LowPrice)
+	volume, _ := parsePrice(stats.Volume)
+	previousClose, _ := parsePrice(stats.PrevClosePrice)
+	change, _ := parsePrice(stats.PriceChange)
+	changePercent, _ := parsePrice(stats.PriceChangePercent)
+
+	return types.Quote{
+		Ticker:           sym.String(),
+		Open:             open / rate,
+		High:             high / rate,
+		Low:              low / rate,
+		Price:            price / rate,
+		Volume:           int64(volume),
+		LatestTradingDay: time.UnixMilli(stats.CloseTime).UTC().Format(history.DateLayout),
+		PreviousClose:    previousClose / rate,
+		Change:           change / rate,
+		ChangePercent:    changePercent,
+	}, nil
+}
+
+// SearchSymbols finds the pair named by query. A bare currency such as BTC
+// is looked up against USD.
+func (s *BinanceService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	sym, err := symbol.Parse(query)
+	if err != nil {
+		return []types.SymbolInfo{}, nil
+	}
+
+	score := 1.0
+	if sym.Asset != symbol.AssetCrypto {
+		if sym.Class != "" || sym.Exchange != "" {
+			return []types.SymbolInfo{}, nil
+		}
+		sym = symbol.Symbol{Base: sym.Base, Quote: "USD", Asset: symbol.AssetCrypto}
+		score = 0.5
+	}
+
+	if _, err := s.pairPrice(ctx, binanceCurrency(sym.Base), binanceCurrency(sym.Quote)); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return []types.SymbolInfo{}, nil
+		}
+		return nil, err
+	}
+
+	info := cryptoSymbolInfo(sym)
+	info.MatchScore = score
+	return []types.SymbolInfo{info}, nil
+}
+
+// FetchSymbol returns the metadata of a pair the exchange prices
+func (s *BinanceService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	sym, err := cryptoSymbol(ticker)
+	if err != nil {
+		return types.SymbolInfo{}, err
+	}
+	if _, err := s.pairPrice(ctx, binanceCurrency(sym.Base), binanceCurrency(sym.Quote)); err != nil {
+		return types.SymbolInfo{}, err
+	}
+	return cryptoSymbolInfo(sym), nil
+}
+
+// FetchPriceHistory returns the klines of a pair's own market. Daily bars
+// are cut at midnight UTC and intraday bars are stamped in UTC.
+func (s *BinanceService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	sym, err := cryptoSymbol(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if interval == "" {
+		interval = IntervalDaily
+	}
+	klineInterval, ok := binanceIntervals[interval]
+	if !ok {
+		return nil, apperror.InvalidArgument("unsupported interval %q", interval)
+	}
+
+	start, end, err := klineRange(interval, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	market := binanceCurrency(sym.Base) + binanceCurrency(sym.Quote)
+	points := make([]types.HistoricalPricePoint, 0)
+	for page := 0; page < binanceMaxKlinePages; page++ {
+		params := url.Values{}
+		params.Set("symbol", market)
+		params.Set("interval", klineInterval)
+		params.Set("limit", strconv.Itoa(binanceKlineLimit))
+		if !start.IsZero() {
+			params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+		}
+		if !end.IsZero() {
+			params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+		}
+
+		var rows [][]interface{}
+		if err := s.get(ctx, "/api/v3/klines", params, &rows); err != nil {
+			return nil, err
+		}
+
+		var last time.Time
+		for _, row := range rows {
+			point, openTime, err := parseKline(row, interval)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, point)
+			last = openTime
+		}
+
+		// Without a start the latest page is all that is asked for
+		if len(rows) < binanceKlineLimit || start.IsZero() {
+			break
+		}
+		start = last.Add(time.Millisecond)
+	}
+
+	return filterBars(points, interval, fromDate, toDate)
+}
+
+// klineRange converts history bounds to the instants klines are requested
+// between. Daily dates are whole UTC days; intraday bounds follow
+// timeBound. Empty bounds give the zero time.
+func klineRange(interval Interval, fromDate, toDate string) (time.Time, time.Time, error) {
+	if interval.Intraday() {
+		start, err := timeBound(fromDate, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end, err := timeBound(toDate, true)
+		return start, end, err
+	}
+
+	from, err := dateBound(fromDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := dateBound(toDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	var start, end time.Time
+	if from != "" {
+		start, _ = time.Parse(history.DateLayout, from)
+	}
+	if to != "" {
+		day, _ := time.Parse(history.DateLayout, to)
+		end = day.AddDate(0, 0, 1).Add(-time.Millisecond)
+	}
+	return start, end, nil
+}
+
+// parseKline converts one kline row: open time, open, high, low, close and
+// volume, followed by fields not used here
+func parseKline(row []interface{}, interval Interval) (types.HistoricalPricePoint, time.Time, error) {
+	if len(row) < 6 {
+		return types.HistoricalPricePoint{}, time.Time{}, apperror.New(apperror.ErrUpstreamUnavailable, "invalid kline: %v", row)
+	}
+	openMillis, ok := row[0].(float64)
+	if !ok {
+		return types.HistoricalPricePoint{}, time.Time{}, apperror.New(apperror.ErrUpstreamUnavailable, "invalid kline open time: %v", row[0])
+	}
+	openTime := time.UnixMilli(int64(openMillis)).UTC()
+
+	open, _ := parsePrice(row[1])
+	high, _ := parsePrice(row[2])
+	low, _ := parsePrice(row[3])
+	close, _ := parsePrice(row[4])
+	volume, _ := parsePrice(row[5])
+
+	date := openTime.Format(time.RFC3339)
+	if !interval.Intraday() {
+		date = openTime.Format(history.DateLayout)
+	}
+	return types.HistoricalPricePoint{
+		Date:   date,
+		Open:   open,
+		High:   high,
+		Low:    low,
+		Close:  close,
+		Volume: int64(volume),
+	}, openTime, nil
+}
+
+// pairPrice returns the price of base in quote currency, crossing through
+// the bridge currency when the pair has no market of its own
+func (s *BinanceService) pairPrice(ctx context.Context, base, quote string) (float64, error) {
+	price, err := s.marketPrice(ctx, base+quote)
+	if !errors.Is(err, errUnknownMarket) || base == binanceBridge || quote == binanceBridge {
+		return price, err
+	}
+
+	rate, err := s.bridgeRate(ctx, quote)
+	if err != nil {
+		return 0, err
+	}
+	basePrice, err := s.marketPrice(ctx, base+binanceBridge)
+	if err != nil {
+		return 0, err
+	}
+	return basePrice / rate, nil
+}
+
+// bridgeRate returns the price of one unit of currency in the bridge
+// currency, from whichever way round the exchange lists the market
+func (s *BinanceService) bridgeRate(ctx context.Context, currency string) (float64, error) {
+	rate, err := s.marketPrice(ctx, currency+binanceBridge)
+	if !errors.Is(err, errUnknownMarket) {
+		return rate, err
+	}
+
+	inverse, err := s.marketPrice(ctx, binanceBridge+currency)
+	if err != nil {
+		return 0, err
+	}
+	return 1 / inverse, nil
+}
+
+// marketPrice returns the last traded price on one market
+func (s *BinanceService) marketPrice(ctx context.Context, market string) (float64, error) {
+	var response struct {
+		Price string `json:"price"`
+	}
+	if err := s.get(ctx, "/api/v3/ticker/price", url.Values{"symbol": {market}}, &response); err != nil {
+		return 0, err
+	}
+
+	price, err := parsePrice(response.Price)
+	if err != nil || price <= 0 {
+		return 0, apperror.New(apperror.ErrUpstreamUnavailable, "invalid price %q for %s", response.Price, market)
+	}
+	return price, nil
+}
+
+// get calls an API endpoint and decodes the JSON response into out. A
+// market that does not exist is reported as errUnknownMarket.
+func (s *BinanceService) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	reqURL := fmt.Sprintf("%s%s?%s", s.baseURL, path, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to call %s", path)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to read response body")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr binanceError
+		json.Unmarshal(body, &apiErr)
+
+		switch {
+		case apiErr.Code == binanceInvalidSymbol:
+			return apperror.Wrap(apperror.ErrNotFound, errUnknownMarket, "no market for %s", params.Get("symbol"))
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot:
+			// Binance answers 418 once an IP keeps going past 429s
+			return apperror.New(apperror.ErrRateLimited, "API returned status %d: %s", resp.StatusCode, string(body))
+		default:
+			return apperror.New(apperror.ErrUpstreamUnavailable, "API returned status %d: %s", resp.StatusCode, string(body))
+		}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return apperror.Wrap(apperror.ErrUpstreamUnavailable, err, "failed to parse response")
+	}
+	return nil
+}
+
+// cryptoSymbol parses ticker, rejecting symbols that are not crypto pairs
+func cryptoSymbol(ticker string) (symbol.Symbol, error) {
+	sym, err := symbol.Parse(ticker)
+	if err != nil {
+		return symbol.Symbol{}, err
+	}
+	if sym.Asset != symbol.AssetCrypto {
+		return symbol.Symbol{}, apperror.NotFound("binance only quotes crypto pairs, not %s", ticker)
+	}
+	return sym, nil
+}
+
+// binanceCurrency translates a currency to Binance's name for it. Binance
+// has no USD markets, so USD pairs are served from USDT ones.
+func binanceCurrency(currency string) string {
+	if currency == "USD" {
+		return binanceBridge
+	}
+	return currency
+}
+
+// cryptoSymbolInfo describes a pair listed on the exchange
+func cryptoSymbolInfo(sym symbol.Symbol) types.SymbolInfo {
+	return types.SymbolInfo{
+		Symbol:   sym.String(),
+		Name:     sym.Base + "/" + sym.Quote,
+		Exchange: "BINANCE",
+		Type:     "Crypto",
+		Region:   "Global",
+		Currency: sym.Quote,
+	}
+}
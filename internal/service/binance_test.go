@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+)
+
+// fakeExchange serves the Binance market data endpoints for a fixed set of
+// markets
+type fakeExchange struct {
+	mu      sync.Mutex
+	prices  map[string]float64
+	klines  map[string][][]interface{}
+	status  int
+	symbols []string
+}
+
+func newFakeExchange(t *testing.T) (*fakeExchange, *BinanceService) {
+	exchange := &fakeExchange{
+		prices: map[string]float64{
+			"BTCUSDT": 43000,
+			"ETHUSDT": 2300,
+			"ETHBTC":  0.0535,
+			"EURUSDT": 1.08,
+			"USDTJPY": 150,
+		},
+		klines: make(map[string][][]interface{}),
+	}
+
+	server := httptest.NewServer(exchange)
+	t.Cleanup(server.Close)
+
+	return exchange, NewBinanceService(server.URL)
+}
+
+func (e *fakeExchange) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	market := r.URL.Query().Get("symbol")
+	e.symbols = append(e.symbols, market)
+
+	if e.status != 0 {
+		w.WriteHeader(e.status)
+		w.Write([]byte(`{"code":-1003,"msg":"Too many requests."}`))
+		return
+	}
+
+	price, ok := e.prices[market]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/v3/ticker/price":
+		json.NewEncoder(w).Encode(map[string]string{"symbol": market, "price": formatFloat(price)})
+	case "/api/v3/ticker/24hr":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"symbol":             market,
+			"priceChange":        formatFloat(price * 0.02),
+			"priceChangePercent": "2.000",
+			"prevClosePrice":     formatFloat(price * 0.98),
+			"lastPrice":          formatFloat(price),
+			"openPrice":          formatFloat(price * 0.98),
+			"highPrice":          formatFloat(price * 1.01),
+			"lowPrice":           formatFloat(price * 0.97),
+			"volume":             "12345.678",
+			"closeTime":          time.Date(2024, 3, 15, 23, 59, 59, 0, time.UTC).UnixMilli(),
+		})
+	case "/api/v3/klines":
+		json.NewEncoder(w).Encode(e.klines[market+r.URL.Query().Get("interval")])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (e *fakeExchange) requested() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.symbols...)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
+
+func TestBinanceService_FetchPrice(t *testing.T) {
+	_, svc := newFakeExchange(t)
+
+	tests := []struct {
+		ticker string
+		want   float64
+	}{
+		{ticker: "BTC-USD", want: 43000},
+		{ticker: "btc/usdt", want: 43000},
+		{ticker: "ETH-BTC", want: 0.0535},
+		// No BTCEUR market: priced through USDT at the EURUSDT rate
+		{ticker: "BTC-EUR", want: 43000 / 1.08},
+		// The bridge market is listed the other way round as USDTJPY
+		{ticker: "BTC-JPY", want: 43000 * 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ticker, func(t *testing.T) {
+			got, err := svc.FetchPrice(context.Background(), tt.ticker)
+			if err != nil {
+				t.Fatalf("FetchPrice(%s) error = %v", tt.ticker, err)
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("FetchPrice(%s) = %v, want %v", tt.ticker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinanceService_NotFound(t *testing.T) {
+	exchange, svc := newFakeExchange(t)
+
+	for _, ticker := range []string{"DOGE-USD", "AAPL", "SHOP.TO"} {
+		if _, err := svc.FetchPrice(context.Background(), ticker); !errors.Is(err, apperror.ErrNotFound) {
+			t.Errorf("FetchPrice(%s) error = %v, want not found", ticker, err)
+		}
+	}
+
+	// Equities are turned away without calling the exchange
+	for _, market := range exchange.requested() {
+		if market != "DOGEUSDT" {
+			t.Errorf("requested market %s, want only DOGEUSDT", market)
+		}
+	}
+}
+
+func TestBinanceService_RateLimited(t *testing.T) {
+	exchange, svc := newFakeExchange(t)
+	exchange.status = http.StatusTooManyRequests
+
+	if _, err := svc.FetchPrice(context.Background(), "BTC-USD"); !errors.Is(err, apperror.ErrRateLimited) {
+		t.Errorf("FetchPrice() error = %v, want rate limited", err)
+	}
+}
+
+func TestBinanceService_FetchQuote(t *testing.T) {
+	_, svc := newFakeExchange(t)
+
+	quote, err := svc.FetchQuote(context.Background(), "btc/eur")
+	if err != nil {
+		t.Fatalf("FetchQuote() error = %v", err)
+	}
+
+	if quote.Ticker != "BTC-EUR" {
+		t.Errorf("Ticker = %s, want BTC-EUR", quote.Ticker)
+	}
+	if want := 43000 / 1.08; math.Abs(quote.Price-want) > 1e-6 {
+		t.Errorf("Price = %v, want %v", quote.Price, want)
+	}
+	if want := 43000 * 0.98 / 1.08; math.Abs(quote.PreviousClose-want) > 1e-6 {
+		t.Errorf("PreviousClose = %v, want %v", quote.PreviousClose, want)
+	}
+	if quote.ChangePercent != 2 {
+		t.Errorf("ChangePercent = %v, want 2", quote.ChangePercent)
+	}
+	if quote.Volume != 12345 {
+		t.Errorf("Volume = %d, want 12345", quote.Volume)
+	}
+	if quote.LatestTradingDay != "2024-03-15" {
+		t.Errorf("LatestTradingDay = %s, want 2024-03-15", quote.LatestTradingDay)
+	}
+}
+
+func TestBinanceService_Symbols(t *testing.T) {
+	_, svc := newFakeExchange(t)
+
+	info, err := svc.FetchSymbol(context.Background(), "eth-usd")
+	if err != nil {
+		t.Fatalf("FetchSymbol() error = %v", err)
+	}
+	if info.Symbol != "ETH-USD" || info.Type != "Crypto" || info.Currency != "USD" {
+		t.Errorf("FetchSymbol() = %+v", info)
+	}
+
+	results, err := svc.SearchSymbols(context.Background(), "btc")
+	if err != nil {
+		t.Fatalf("SearchSymbols() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Symbol != "BTC-USD" {
+		t.Errorf("SearchSymbols(btc) = %+v, want BTC-USD", results)
+	}
+
+	results, err = svc.SearchSymbols(context.Background(), "Apple Inc")
+	if err != nil || len(results) != 0 {
+		t.Errorf("SearchSymbols(Apple Inc) = %+v, %v; want no results", results, err)
+	}
+}
+
+func TestBinanceService_FetchPriceHistory(t *testing.T) {
+	exchange, svc := newFakeExchange(t)
+
+	day := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		open := day.AddDate(0, 0, i).UnixMilli()
+		exchange.klines["BTCUSDT1d"] = append(exchange.klines["BTCUSDT1d"], []interface{}{
+			open, "42000.00", "44000.00", "41000.00", fmt.Sprintf("%d.50", 43000+i), "1500.75", open + 86399999, "0", 100,
+		})
+	}
+
+	points, err := svc.FetchPriceHistory(context.Background(), "BTC-USD", IntervalDaily, "2024-03-14", "2024-03-15")
+	if err != nil {
+		t.Fatalf("FetchPriceHistory() error = %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if points[0].Date != "2024-03-14" || points[0].Close != 43001.5 || points[0].Volume != 1500 {
+		t.Errorf("points[0] = %+v", points[0])
+	}
+	if points[1].Date != "2024-03-15" {
+		t.Errorf("points[1].Date = %s, want 2024-03-15", points[1].Date)
+	}
+
+	if _, err := svc.FetchPriceHistory(context.Background(), "BTC-USD", Interval("2h"), "", ""); !errors.Is(err, apperror.ErrInvalidArgument) {
+		t.Errorf("FetchPriceHistory(2h) error = %v, want invalid argument", err)
+	}
+}
+
+func TestBinanceService_AlertChecker(t *testing.T) {
+	_, svc := newFakeExchange(t)
+
+	// Equities fall through to the mock provider, crypto pairs to the exchange
+	registry := NewProviderRegistry(time.Second)
+	registry.Register(ProviderBinance, svc)
+	registry.Register(ProviderMock, &priceService{})
+
+	webhooks := make(chan map[string]interface{}, 2)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		webhooks <- payload
+	}))
+	defer hook.Close()
+
+	alerts := NewAlertService(registry)
+	if _, err := alerts.CreateAlert("btc/usd", ConditionAbove, 40000, hook.URL); err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if _, err := alerts.CreateAlert("ETH-EUR", ConditionBelow, 1000, hook.URL); err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+
+	if err := alerts.CheckAlerts(context.Background()); err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+
+	select {
+	case payload := <-webhooks:
+		if payload["ticker"] != "BTC-USD" || payload["current_price"] != 43000.0 {
+			t.Errorf("webhook payload = %v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a webhook for BTC-USD")
+	}
+
+	select {
+	case payload := <-webhooks:
+		t.Errorf("unexpected webhook %v", payload)
+	default:
+	}
+}
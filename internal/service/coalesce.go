@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// coalesceMetrics counts upstream calls made and caller requests that were
+// served by joining a call already in flight
+var coalesceMetrics = expvar.NewMap("coalesced_requests")
+
+// flightTimeout bounds a shared upstream call. The call does not take the
+// deadline of any one caller, so that a caller in a hurry does not fail the
+// others; each caller still gives up at its own deadline.
+var flightTimeout = 30 * time.Second
+
+// flightGroup collapses concurrent calls with the same key into a single
+// call whose result is shared. The zero value is ready to use.
+type flightGroup[T any] struct {
+	metric string
+	// limiter is the rate limiter the calls wait on, if any. Callers are
+	// checked against it so that one who cannot get an upstream slot before
+	// its own deadline gets a QuotaExhaustedError.
+	limiter *RateLimiter
+	mutex   sync.Mutex
+	calls   map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+	ctx     *flightContext
+	cancel  context.CancelFunc
+}
+
+// Do runs fn once for all concurrent callers using key. The shared call is
+// detached from any single caller: it runs for up to flightTimeout, its
+// upstream requests queue at the priority of the most urgent caller waiting
+// when they are made, a caller whose context ends gets its context error
+// back while the others keep waiting, and the call itself is cancelled only
+// once every caller has gone. A caller that would wait on the limiter past
+// its deadline, or whose deadline passes while the call is queued on it,
+// gets a QuotaExhaustedError instead of its context error.
+func (g *flightGroup[T]) Do(ctx context.Context, key string, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+
+	priority := priorityFromContext(ctx)
+	call, exists := g.calls[key]
+	if !exists || call.ctx.queued.Load() > 0 {
+		// The caller would wait for an upstream slot
+		if err := g.limiter.Check(ctx); err != nil {
+			g.mutex.Unlock()
+			return zero, err
+		}
+	}
+	if exists {
+		call.waiters++
+		call.ctx.join(priority)
+		g.count("deduplicated")
+	} else {
+		shared := newFlightContext(ctx)
+		shared.join(priority)
+		callCtx, cancel := context.WithTimeout(shared, flightTimeout)
+		call = &flightCall[T]{done: make(chan struct{}), waiters: 1, ctx: shared, cancel: cancel}
+		g.calls[key] = call
+		g.count("upstream")
+
+		go func() {
+			call.val, call.err = fn(callCtx)
+			cancel()
+
+			g.mutex.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mutex.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		g.mutex.Lock()
+		queued := call.ctx.queued.Load() > 0
+		call.waiters--
+		call.ctx.leave(priority)
+		if call.waiters == 0 {
+			// Nobody is left to use the result
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mutex.Unlock()
+
+		if queued && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return zero, &QuotaExhaustedError{RetryAfter: g.limiter.nextSlot()}
+		}
+		return zero, ctx.Err()
+	}
+}
+
+func (g *flightGroup[T]) count(what string) {
+	if g.metric != "" {
+		coalesceMetrics.Add(g.metric+"_"+what, 1)
+	}
+}
+
+// flightContext is the context a shared call runs on. It keeps the values
+// of the caller that started the call, such as its request ID, but not its
+// cancellation or response metadata, and reports the highest priority of
+// the callers waiting on the call.
+type flightContext struct {
+	context.Context
+	// waiting counts the callers at each priority; guarded by the group's
+	// mutex
+	waiting  map[Priority]int
+	priority atomic.Int64
+	// queued counts the call's requests waiting on the rate limiter
+	queued atomic.Int32
+}
+
+type flightContextKey struct{}
+
+// flightFromContext returns the shared call ctx belongs to, if any
+func flightFromContext(ctx context.Context) *flightContext {
+	call, _ := ctx.Value(flightContextKey{}).(*flightContext)
+	return call
+}
+
+// markQueued marks the shared calls ctx belongs to, including any call
+// that started them, as waiting on the rate limiter until the returned
+// function is called
+func markQueued(ctx context.Context) func() {
+	var calls []*flightContext
+	for call := flightFromContext(ctx); call != nil; call = flightFromContext(call.Context) {
+		call.queued.Add(1)
+		calls = append(calls, call)
+	}
+	return func() {
+		for _, call := range calls {
+			call.queued.Add(-1)
+		}
+	}
+}
+
+func newFlightContext(ctx context.Context) *flightContext {
+	return &flightContext{Context: context.WithoutCancel(ctx), waiting: make(map[Priority]int)}
+}
+
+func (c *flightContext) Value(key any) any {
+	switch key.(type) {
+	case responseMetaKey:
+		// Each caller records how it was answered on its own metadata
+		return nil
+	case priorityKey:
+		return Priority(c.priority.Load())
+	case flightContextKey:
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// join and leave track the callers waiting at a priority
+func (c *flightContext) join(p Priority) {
+	c.waiting[p]++
+	c.updatePriority()
+}
+
+func (c *flightContext) leave(p Priority) {
+	if c.waiting[p]--; c.waiting[p] <= 0 {
+		delete(c.waiting, p)
+	}
+	c.updatePriority()
+}
+
+func (c *flightContext) updatePriority() {
+	highest := PriorityBackground
+	for p := range c.waiting {
+		if p > highest {
+			highest = p
+		}
+	}
+	c.priority.Store(int64(highest))
+}
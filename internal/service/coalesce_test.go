@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlightGroup_CollapsesConcurrentCalls(t *testing.T) {
+	var group flightGroup[float64]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (float64, error) {
+		calls.Add(1)
+		<-release
+		return 150.0, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan float64, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			price, err := group.Do(context.Background(), "AAPL", fn)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results <- price
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Upstream calls = %d, want 1", got)
+	}
+	for price := range results {
+		if price != 150.0 {
+			t.Errorf("Do() = %v, want 150.0", price)
+		}
+	}
+}
+
+func TestFlightGroup_CallerCancellation(t *testing.T) {
+	var group flightGroup[float64]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func(ctx context.Context) (float64, error) {
+		close(started)
+		select {
+		case <-release:
+			return 150.0, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := group.Do(ctx, "AAPL", fn)
+		cancelled <- err
+	}()
+	<-started
+
+	patient := make(chan float64, 1)
+	go func() {
+		price, _ := group.Do(context.Background(), "AAPL", fn)
+		patient <- price
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("Cancelled caller error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if price := <-patient; price != 150.0 {
+		t.Errorf("Remaining caller got %v, want 150.0", price)
+	}
+}
+
+func TestFlightGroup_LastCallerCancelsCall(t *testing.T) {
+	var group flightGroup[float64]
+	upstreamDone := make(chan error, 1)
+
+	fn := func(ctx context.Context) (float64, error) {
+		<-ctx.Done()
+		upstreamDone <- ctx.Err()
+		return 0, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go group.Do(ctx, "AAPL", fn)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-upstreamDone:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Upstream context error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected upstream call to be cancelled once all callers left")
+	}
+}
+
+func TestFlightGroup_CallerDeadlines(t *testing.T) {
+	var group flightGroup[float64]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func(ctx context.Context) (float64, error) {
+		close(started)
+		select {
+		case <-release:
+			return 150.0, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// The caller starting the call is in a hurry; the one joining it is not
+	hurried, cancelHurried := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelHurried()
+	hurriedErr := make(chan error, 1)
+	go func() {
+		_, err := group.Do(hurried, "AAPL", fn)
+		hurriedErr <- err
+	}()
+	<-started
+
+	patient, cancelPatient := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelPatient()
+	patientResult := make(chan error, 1)
+	go func() {
+		price, err := group.Do(patient, "AAPL", fn)
+		if err == nil && price != 150.0 {
+			t.Errorf("Do() = %v, want 150.0", price)
+		}
+		patientResult <- err
+	}()
+
+	if err := <-hurriedErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Hurried caller error = %v, want context.DeadlineExceeded", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-patientResult; err != nil {
+		t.Errorf("Patient caller error = %v, want the shared result", err)
+	}
+}
+
+func TestFlightGroup_QuotaExhaustedWhileQueued(t *testing.T) {
+	limiter := newRateLimiter(newTokenBucket(1, 100*time.Millisecond))
+	group := flightGroup[float64]{limiter: limiter}
+
+	// The call needs two slots, so the caller's deadline passes while the
+	// call waits for the second one
+	fn := func(ctx context.Context) (float64, error) {
+		for i := 0; i < 2; i++ {
+			if err := limiter.Wait(ctx); err != nil {
+				return 0, err
+			}
+		}
+		return 150.0, nil
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	_, err := group.Do(ctx, "AAPL", fn)
+	var quotaErr *QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Do() error = %v, want QuotaExhaustedError", err)
+	}
+	if quotaErr.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want positive", quotaErr.RetryAfter)
+	}
+
+	// A caller that cannot be served before its deadline is turned away
+	// without joining
+	hurried, cancelHurried := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelHurried()
+	called := false
+	_, err = group.Do(hurried, "MSFT", func(ctx context.Context) (float64, error) {
+		called = true
+		return fn(ctx)
+	})
+	if !errors.Is(err, ErrQuotaExhausted) || called {
+		t.Errorf("Do() error = %v, called = %v; want ErrQuotaExhausted without a call", err, called)
+	}
+}
+
+func TestFlightGroup_SharedCallContext(t *testing.T) {
+	var group flightGroup[float64]
+	release := make(chan struct{})
+	started := make(chan context.Context, 1)
+
+	fn := func(ctx context.Context) (float64, error) {
+		started <- ctx
+		<-release
+		return 150.0, nil
+	}
+
+	background, _ := WithResponseMeta(WithPriority(context.Background(), PriorityBackground))
+	done := make(chan struct{})
+	go func() {
+		group.Do(background, "AAPL", fn)
+		close(done)
+	}()
+	callCtx := <-started
+
+	if _, ok := callCtx.Deadline(); !ok {
+		t.Error("Shared call has no deadline, want flightTimeout")
+	}
+	if ResponseMetaFromContext(callCtx) != nil {
+		t.Error("Shared call carries the first caller's response metadata")
+	}
+	if p := priorityFromContext(callCtx); p != PriorityBackground {
+		t.Errorf("Priority = %v, want background", p)
+	}
+
+	// An interactive caller joining raises the priority of the call
+	interactive, cancel := context.WithCancel(context.Background())
+	go group.Do(interactive, "AAPL", fn)
+	time.Sleep(10 * time.Millisecond)
+	if p := priorityFromContext(callCtx); p != PriorityInteractive {
+		t.Errorf("Priority after interactive caller joined = %v, want interactive", p)
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if p := priorityFromContext(callCtx); p != PriorityBackground {
+		t.Errorf("Priority after interactive caller left = %v, want background", p)
+	}
+
+	close(release)
+	<-done
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// RateService provides foreign exchange rates, as units of the to currency
+// per unit of the from currency
+type RateService interface {
+	FetchRate(ctx context.Context, from, to string) (float64, error)
+	// FetchRateHistory returns the daily closing rates within the range,
+	// keyed by YYYY-MM-DD date. Days without trading have no rate.
+	FetchRateHistory(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error)
+}
+
+// mockRates are the mock rates against USD. They also list the currencies
+// prices can be converted to.
+var mockRates = map[string]float64{
+	"USD": 1,
+	"EUR": 0.92,
+	"GBP": 0.79,
+	"JPY": 150,
+	"CAD": 1.35,
+	"AUD": 1.52,
+	"CHF": 0.88,
+	"HKD": 7.8,
+	"CNY": 7.2,
+	"INR": 83,
+}
+
+// minorUnits are currencies prices are quoted in that are a fraction of
+// another, such as pence for London listings
+var minorUnits = map[string]struct {
+	major  string
+	factor float64
+}{
+	"GBX": {major: "GBP", factor: 0.01},
+}
+
+// peggedCurrencies are stablecoins crypto pairs are quoted in, converted as
+// the currency they track
+var peggedCurrencies = map[string]string{
+	"USDT": "USD",
+	"USDC": "USD",
+}
+
+// rateLookback is how far before the first bar rates are requested, so
+// that bars on days without a rate of their own can use an earlier one
+const rateLookback = 7 * 24 * time.Hour
+
+// ParseCurrency validates a currency prices are to be converted to
+func ParseCurrency(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if _, ok := mockRates[currency]; !ok {
+		return "", apperror.InvalidArgument("unsupported currency %q", value)
+	}
+	return currency, nil
+}
+
+// fxCurrency returns the currency rates for currency are quoted in and the
+// factor converting amounts to it
+func fxCurrency(currency string) (string, float64, error) {
+	if minor, ok := minorUnits[currency]; ok {
+		return minor.major, minor.factor, nil
+	}
+	if pegged, ok := peggedCurrencies[currency]; ok {
+		return pegged, 1, nil
+	}
+	if _, ok := mockRates[currency]; !ok {
+		return "", 0, apperror.InvalidArgument("prices in %s cannot be converted", currency)
+	}
+	return currency, 1, nil
+}
+
+// ExchangeRate returns the current rate converting amounts in from to to.
+// Minor units and stablecoins are converted through the currency they are
+// based on.
+func ExchangeRate(ctx context.Context, rates RateService, from, to string) (float64, error) {
+	fromFX, fromFactor, err := fxCurrency(from)
+	if err != nil {
+		return 0, err
+	}
+	toFX, toFactor, err := fxCurrency(to)
+	if err != nil {
+		return 0, err
+	}
+
+	rate := 1.0
+	if fromFX != toFX {
+		if rate, err = rates.FetchRate(ctx, fromFX, toFX); err != nil {
+			return 0, err
+		}
+	}
+	return rate * fromFactor / toFactor, nil
+}
+
+// ConvertBars converts bars from one currency to another at the closing
+// rate of each bar's date. Bars on days without a rate, such as weekend
+// crypto bars, use the latest earlier one. Volume is left as traded.
+func ConvertBars(ctx context.Context, rates RateService, points []types.HistoricalPricePoint, from, to string) ([]types.HistoricalPricePoint, error) {
+	fromFX, fromFactor, err := fxCurrency(from)
+	if err != nil {
+		return nil, err
+	}
+	toFX, toFactor, err := fxCurrency(to)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return points, nil
+	}
+
+	dailyRate := func(string) (float64, error) { return 1, nil }
+	if fromFX != toFX {
+		first, err := time.Parse(history.DateLayout, barDate(points[0]))
+		if err != nil {
+			return nil, apperror.InvalidArgument("invalid bar date %q", points[0].Date)
+		}
+		daily, err := rates.FetchRateHistory(ctx, fromFX, toFX,
+			first.Add(-rateLookback).Format(history.DateLayout), barDate(points[len(points)-1]))
+		if err != nil {
+			return nil, err
+		}
+		dailyRate = rateOn(daily, fromFX, toFX)
+	}
+
+	converted := make([]types.HistoricalPricePoint, len(points))
+	for i, p := range points {
+		rate, err := dailyRate(barDate(p))
+		if err != nil {
+			return nil, err
+		}
+		rate *= fromFactor / toFactor
+
+		p.Open *= rate
+		p.High *= rate
+		p.Low *= rate
+		p.Close *= rate
+		p.AdjustedClose *= rate
+		p.DividendAmount *= rate
+		converted[i] = p
+	}
+	return converted, nil
+}
+
+// rateOn returns a lookup of the rate in effect on a date: the rate of that
+// date or the latest before it
+func rateOn(rates map[string]float64, from, to string) func(string) (float64, error) {
+	dates := make([]string, 0, len(rates))
+	for date := range rates {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	return func(date string) (float64, error) {
+		i := sort.SearchStrings(dates, date)
+		if i < len(dates) && dates[i] == date {
+			return rates[date], nil
+		}
+		if i == 0 {
+			return 0, apperror.New(apperror.ErrUpstreamUnavailable, "no %s/%s rate on or before %s", from, to, date)
+		}
+		return rates[dates[i-1]], nil
+	}
+}
+
+// barDate returns the date of a bar. Intraday bars are dated by the local
+// date of their RFC3339 time.
+func barDate(p types.HistoricalPricePoint) string {
+	if len(p.Date) > len(history.DateLayout) {
+		return p.Date[:len(history.DateLayout)]
+	}
+	return p.Date
+}
+
+func (s *priceService) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	return MockRateFetcher(ctx, from, to)
+}
+
+func (s *priceService) FetchRateHistory(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error) {
+	return MockRateHistoryFetcher(ctx, from, to, fromDate, toDate)
+}
+
+// MockRateFetcher crosses the mock rates of both currencies against USD
+func MockRateFetcher(ctx context.Context, from, to string) (float64, error) {
+	fromRate, ok := mockRates[from]
+	if !ok {
+		return 0, apperror.NotFound("no rate for %s", from)
+	}
+	toRate, ok := mockRates[to]
+	if !ok {
+		return 0, apperror.NotFound("no rate for %s", to)
+	}
+	return toRate / fromRate, nil
+}
+
+// MockRateHistoryFetcher returns a rate for each weekday in the range,
+// swinging within 1% of the mock rate. Without a from date the range starts
+// 30 days before its end, which defaults to today.
+func MockRateHistoryFetcher(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error) {
+	rate, err := MockRateFetcher(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now().UTC()
+	if toDate != "" {
+		if end, err = time.Parse(history.DateLayout, toDate); err != nil {
+			return nil, apperror.InvalidArgument("invalid to date %q", toDate)
+		}
+	}
+	start := end.AddDate(0, 0, -30)
+	if fromDate != "" {
+		if start, err = time.Parse(history.DateLayout, fromDate); err != nil {
+			return nil, apperror.InvalidArgument("invalid from date %q", fromDate)
+		}
+	}
+
+	rates := make(map[string]float64)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		swing := 1 + 0.01*math.Sin(float64(day.Unix()/86400)/5)
+		rates[day.Format(history.DateLayout)] = rate * swing
+	}
+	return rates, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// fixedRates serves one rate per date and counts calls
+type fixedRates struct {
+	spot    float64
+	history map[string]float64
+	calls   int
+}
+
+func (r *fixedRates) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	r.calls++
+	return r.spot, nil
+}
+
+func (r *fixedRates) FetchRateHistory(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error) {
+	r.calls++
+	rates := make(map[string]float64)
+	for date, rate := range r.history {
+		if date >= fromDate && date <= toDate {
+			rates[date] = rate
+		}
+	}
+	return rates, nil
+}
+
+func TestExchangeRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		want      float64
+		wantCalls int
+	}{
+		{name: "Rate", from: "USD", to: "EUR", want: 0.9, wantCalls: 1},
+		{name: "Same currency", from: "EUR", to: "EUR", want: 1},
+		{name: "Stablecoin", from: "USDT", to: "USD", want: 1},
+		{name: "Pence", from: "GBX", to: "EUR", want: 0.009, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := &fixedRates{spot: 0.9}
+			got, err := ExchangeRate(context.Background(), rates, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("ExchangeRate() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ExchangeRate() = %v, want %v", got, tt.want)
+			}
+			if rates.calls != tt.wantCalls {
+				t.Errorf("rate fetches = %d, want %d", rates.calls, tt.wantCalls)
+			}
+		})
+	}
+
+	if _, err := ExchangeRate(context.Background(), &fixedRates{}, "BTC", "USD"); !errors.Is(err, apperror.ErrInvalidArgument) {
+		t.Errorf("ExchangeRate(BTC) error = %v, want invalid argument", err)
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	if got, err := ParseCurrency(" eur "); err != nil || got != "EUR" {
+		t.Errorf("ParseCurrency(eur) = %q, %v; want EUR", got, err)
+	}
+	for _, value := range []string{"", "XYZ", "GBX", "BTC"} {
+		if _, err := ParseCurrency(value); !errors.Is(err, apperror.ErrInvalidArgument) {
+			t.Errorf("ParseCurrency(%q) error = %v, want invalid argument", value, err)
+		}
+	}
+}
+
+func TestConvertBars(t *testing.T) {
+	rates := &fixedRates{history: map[string]float64{
+		"2024-01-04": 0.5,
+		"2024-01-05": 0.8,
+		"2024-01-08": 0.9,
+	}}
+	points := []types.HistoricalPricePoint{
+		{Date: "2024-01-05", Open: 10, High: 20, Low: 5, Close: 15, Volume: 100, AdjustedClose: 14, DividendAmount: 1},
+		// Weekend bars use Friday's rate
+		{Date: "2024-01-06", Close: 10, Volume: 200},
+		{Date: "2024-01-08T10:00:00-05:00", Close: 10},
+	}
+
+	got, err := ConvertBars(context.Background(), rates, points, "USD", "EUR")
+	if err != nil {
+		t.Fatalf("ConvertBars() error = %v", err)
+	}
+
+	want := []types.HistoricalPricePoint{
+		{Date: "2024-01-05", Open: 8, High: 16, Low: 4, Close: 12, Volume: 100, AdjustedClose: 11.2, DividendAmount: 0.8},
+		{Date: "2024-01-06", Close: 8, Volume: 200},
+		{Date: "2024-01-08T10:00:00-05:00", Close: 9},
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Date != w.Date || g.Volume != w.Volume ||
+			math.Abs(g.Open-w.Open) > 1e-9 || math.Abs(g.High-w.High) > 1e-9 || math.Abs(g.Low-w.Low) > 1e-9 ||
+			math.Abs(g.Close-w.Close) > 1e-9 || math.Abs(g.AdjustedClose-w.AdjustedClose) > 1e-9 ||
+			math.Abs(g.DividendAmount-w.DividendAmount) > 1e-9 {
+			t.Errorf("bar %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if rates.calls != 1 {
+		t.Errorf("rate fetches = %d, want 1", rates.calls)
+	}
+
+	// The input is left unchanged
+	if points[0].Close != 15 {
+		t.Errorf("input bar modified: %+v", points[0])
+	}
+}
+
+func TestConvertBars_NoEarlierRate(t *testing.T) {
+	rates := &fixedRates{history: map[string]float64{"2024-01-05": 0.8}}
+	points := []types.HistoricalPricePoint{{Date: "2024-01-03", Close: 10}}
+
+	if _, err := ConvertBars(context.Background(), rates, points, "USD", "EUR"); !errors.Is(err, apperror.ErrUpstreamUnavailable) {
+		t.Errorf("ConvertBars() error = %v, want upstream unavailable", err)
+	}
+}
+
+func TestMockRates(t *testing.T) {
+	rate, err := MockRateFetcher(context.Background(), "EUR", "GBP")
+	if err != nil {
+		t.Fatalf("MockRateFetcher() error = %v", err)
+	}
+	if want := 0.79 / 0.92; math.Abs(rate-want) > 1e-9 {
+		t.Errorf("MockRateFetcher(EUR, GBP) = %v, want %v", rate, want)
+	}
+
+	history, err := MockRateHistoryFetcher(context.Background(), "USD", "EUR", "2024-01-01", "2024-01-07")
+	if err != nil {
+		t.Fatalf("MockRateHistoryFetcher() error = %v", err)
+	}
+	if len(history) != 5 {
+		t.Errorf("len(history) = %d, want 5 weekdays", len(history))
+	}
+	for date, r := range history {
+		if math.Abs(r/0.92-1) > 0.01+1e-9 {
+			t.Errorf("rate on %s = %v, want within 1%% of 0.92", date, r)
+		}
+	}
+
+	// Every mock price converts to every supported currency
+	for ticker := range priceMocks {
+		for currency := range mockRates {
+			if _, err := ExchangeRate(context.Background(), &priceService{}, mockCurrency(t, ticker), currency); err != nil {
+				t.Errorf("ExchangeRate(%s, %s) error = %v", ticker, currency, err)
+			}
+		}
+	}
+}
+
+func mockCurrency(t *testing.T, ticker string) string {
+	t.Helper()
+	info, err := MockSymbolFetcher(context.Background(), ticker)
+	if err != nil {
+		t.Fatalf("MockSymbolFetcher(%s) error = %v", ticker, err)
+	}
+	return info.Currency
+}
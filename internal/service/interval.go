@@ -0,0 +1,167 @@
+package service
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // market hours are in US/Eastern regardless of host setup
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// Interval is the bar size of a price history
+type Interval string
+
+// Supported history intervals
+const (
+	IntervalDaily Interval = "daily"
+	Interval1Min  Interval = "1min"
+	Interval5Min  Interval = "5min"
+	Interval15Min Interval = "15min"
+	Interval30Min Interval = "30min"
+	Interval60Min Interval = "60min"
+)
+
+// marketLocation is the time zone of US exchange sessions, in which
+// intraday bars are reported
+var marketLocation = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// ParseInterval parses an interval name. An empty name means daily bars.
+func ParseInterval(name string) (Interval, error) {
+	switch interval := Interval(name); interval {
+	case "":
+		return IntervalDaily, nil
+	case IntervalDaily, Interval1Min, Interval5Min, Interval15Min, Interval30Min, Interval60Min:
+		return interval, nil
+	default:
+		return "", apperror.InvalidArgument("unsupported interval %q: use daily, 1min, 5min, 15min, 30min or 60min", name)
+	}
+}
+
+// Intraday reports whether bars are shorter than a day. Intraday bars are
+// stamped with RFC3339 times, daily bars with YYYY-MM-DD dates.
+func (i Interval) Intraday() bool {
+	return i != "" && i != IntervalDaily
+}
+
+// Duration returns the length of one bar
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1Min:
+		return time.Minute
+	case Interval5Min:
+		return 5 * time.Minute
+	case Interval15Min:
+		return 15 * time.Minute
+	case Interval30Min:
+		return 30 * time.Minute
+	case Interval60Min:
+		return time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// ValidateHistoryBound checks a history range bound, which is either a
+// YYYY-MM-DD date or an RFC3339 time
+func ValidateHistoryBound(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.Parse(history.DateLayout, value); err == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return nil
+	}
+	return apperror.InvalidArgument("invalid time %q: use YYYY-MM-DD or RFC3339", value)
+}
+
+// dateBound converts a range bound to the YYYY-MM-DD form daily bars are
+// compared against
+func dateBound(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(history.DateLayout, value); err == nil {
+		return value, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", ValidateHistoryBound(value)
+	}
+	return t.In(marketLocation).Format(history.DateLayout), nil
+}
+
+// timeBound converts a range bound to an instant. A date means midnight in
+// the market time zone, or the end of that day for an upper bound. The zero
+// time is returned for an empty bound.
+func timeBound(value string, upper bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.ParseInLocation(history.DateLayout, value, marketLocation); err == nil {
+		if upper {
+			return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return day, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, ValidateHistoryBound(value)
+	}
+	return t, nil
+}
+
+// filterBars keeps the bars between fromDate and toDate inclusive. Empty
+// bounds are open.
+func filterBars(points []types.HistoricalPricePoint, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	if !interval.Intraday() {
+		from, err := dateBound(fromDate)
+		if err != nil {
+			return nil, err
+		}
+		to, err := dateBound(toDate)
+		if err != nil {
+			return nil, err
+		}
+
+		filtered := make([]types.HistoricalPricePoint, 0, len(points))
+		for _, p := range points {
+			if (from == "" || p.Date >= from) && (to == "" || p.Date <= to) {
+				filtered = append(filtered, p)
+			}
+		}
+		return filtered, nil
+	}
+
+	from, err := timeBound(fromDate, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := timeBound(toDate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]types.HistoricalPricePoint, 0, len(points))
+	for _, p := range points {
+		at, err := time.Parse(time.RFC3339, p.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bar time %q: %w", p.Date, err)
+		}
+		if (from.IsZero() || !at.Before(from)) && (to.IsZero() || !at.After(to)) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
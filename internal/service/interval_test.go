@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Interval
+		wantErr bool
+	}{
+		{name: "Empty means daily", input: "", want: IntervalDaily},
+		{name: "Daily", input: "daily", want: IntervalDaily},
+		{name: "Five minutes", input: "5min", want: Interval5Min},
+		{name: "Hourly", input: "60min", want: Interval60Min},
+		{name: "Unsupported", input: "2min", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInterval(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInterval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInterval() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterBars(t *testing.T) {
+	intraday := []types.HistoricalPricePoint{
+		{Date: "2024-01-02T15:55:00-05:00"},
+		{Date: "2024-01-03T09:30:00-05:00"},
+		{Date: "2024-01-03T16:00:00-05:00"},
+		{Date: "2024-01-04T09:30:00-05:00"},
+	}
+	daily := []types.HistoricalPricePoint{
+		{Date: "2024-01-02"},
+		{Date: "2024-01-03"},
+		{Date: "2024-01-04"},
+	}
+
+	tests := []struct {
+		name     string
+		points   []types.HistoricalPricePoint
+		interval Interval
+		fromDate string
+		toDate   string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "Intraday with a whole day",
+			points:   intraday,
+			interval: Interval5Min,
+			fromDate: "2024-01-03",
+			toDate:   "2024-01-03",
+			want:     []string{"2024-01-03T09:30:00-05:00", "2024-01-03T16:00:00-05:00"},
+		},
+		{
+			name:     "Intraday with times in another zone",
+			points:   intraday,
+			interval: Interval5Min,
+			fromDate: "2024-01-02T20:55:00Z",
+			toDate:   "2024-01-03T14:30:00Z",
+			want:     []string{"2024-01-02T15:55:00-05:00", "2024-01-03T09:30:00-05:00"},
+		},
+		{
+			name:     "Daily with a time bound",
+			points:   daily,
+			interval: IntervalDaily,
+			fromDate: "2024-01-03T02:00:00Z",
+			want:     []string{"2024-01-02", "2024-01-03", "2024-01-04"},
+		},
+		{
+			name:     "Malformed bound",
+			points:   daily,
+			interval: IntervalDaily,
+			fromDate: "yesterday",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterBars(tt.points, tt.interval, tt.fromDate, tt.toDate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterBars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterBars() = %v, want %v", got, tt.want)
+			}
+			for i, date := range tt.want {
+				if got[i].Date != date {
+					t.Errorf("filterBars()[%d] = %s, want %s", i, got[i].Date, date)
+				}
+			}
+		})
+	}
+}
+
+func TestMockPriceHistoryFetcher_Intraday(t *testing.T) {
+	bars, err := MockPriceHistoryFetcher(context.Background(), "AAPL", Interval15Min, "2024-01-03", "2024-01-03")
+	if err != nil {
+		t.Fatalf("MockPriceHistoryFetcher() error = %v", err)
+	}
+
+	// 09:30 to 16:00 in 15 minute bars
+	if len(bars) != 26 {
+		t.Fatalf("MockPriceHistoryFetcher() returned %d bars, want 26", len(bars))
+	}
+
+	first, err := time.Parse(time.RFC3339, bars[0].Date)
+	if err != nil {
+		t.Fatalf("Bar time %q is not RFC3339: %v", bars[0].Date, err)
+	}
+	if want := time.Date(2024, 1, 3, 14, 30, 0, 0, time.UTC); !first.Equal(want) {
+		t.Errorf("First bar at %v, want %v", first, want)
+	}
+
+	for i, bar := range bars {
+		if bar.Low < 147 || bar.High > 158 || bar.Low > bar.High {
+			t.Errorf("Bar %d = %+v, outside the day's range", i, bar)
+		}
+	}
+	if last := bars[len(bars)-1]; last.Close != 155 {
+		t.Errorf("Last bar close = %v, want the day's close 155", last.Close)
+	}
+}
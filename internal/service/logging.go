@@ -37,11 +37,50 @@ func (s LoggingService) FetchPrices(ctx context.Context, tickers []string) (pric
 	return s.next.FetchPrices(ctx, tickers)
 }
 
-func (s LoggingService) FetchPriceHistory(ctx context.Context, ticker, fromDate, toDate string) (history []types.HistoricalPricePoint, err error) {
+func (s LoggingService) FetchQuote(ctx context.Context, ticker string) (quote types.Quote, err error) {
 	defer func(begin time.Time) {
 		logrus.WithFields(logrus.Fields{
 			"requestID": ctx.Value("requestID"),
 			"ticker":    ticker,
+			"price":     quote.Price,
+			"took":      time.Since(begin),
+			"err":       err,
+		}).Info("fetch quote")
+	}(time.Now())
+	return s.next.FetchQuote(ctx, ticker)
+}
+
+func (s LoggingService) SearchSymbols(ctx context.Context, query string) (matches []types.SymbolInfo, err error) {
+	defer func(begin time.Time) {
+		logrus.WithFields(logrus.Fields{
+			"requestID": ctx.Value("requestID"),
+			"query":     query,
+			"count":     len(matches),
+			"took":      time.Since(begin),
+			"err":       err,
+		}).Info("search symbols")
+	}(time.Now())
+	return s.next.SearchSymbols(ctx, query)
+}
+
+func (s LoggingService) FetchSymbol(ctx context.Context, ticker string) (info types.SymbolInfo, err error) {
+	defer func(begin time.Time) {
+		logrus.WithFields(logrus.Fields{
+			"requestID": ctx.Value("requestID"),
+			"ticker":    ticker,
+			"took":      time.Since(begin),
+			"err":       err,
+		}).Info("fetch symbol")
+	}(time.Now())
+	return s.next.FetchSymbol(ctx, ticker)
+}
+
+func (s LoggingService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) (history []types.HistoricalPricePoint, err error) {
+	defer func(begin time.Time) {
+		logrus.WithFields(logrus.Fields{
+			"requestID": ctx.Value("requestID"),
+			"ticker":    ticker,
+			"interval":  interval,
 			"from":      fromDate,
 			"to":        toDate,
 			"count":     len(history),
@@ -49,7 +88,7 @@ func (s LoggingService) FetchPriceHistory(ctx context.Context, ticker, fromDate,
 			"err":       err,
 		}).Info("fetch price history")
 	}(time.Now())
-	return s.next.FetchPriceHistory(ctx, ticker, fromDate, toDate)
+	return s.next.FetchPriceHistory(ctx, ticker, interval, fromDate, toDate)
 }
 
 func NewLoggingService(next PriceService) PriceService {
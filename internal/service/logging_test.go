@@ -25,7 +25,19 @@ func (m *mockPriceService) FetchPrices(ctx context.Context, tickers []string) (m
 	return nil, m.err
 }
 
-func (m *mockPriceService) FetchPriceHistory(ctx context.Context, ticker, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+func (m *mockPriceService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	return types.Quote{Ticker: ticker, Price: m.price}, m.err
+}
+
+func (m *mockPriceService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	return []types.SymbolInfo{}, m.err
+}
+
+func (m *mockPriceService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	return types.SymbolInfo{Symbol: ticker}, m.err
+}
+
+func (m *mockPriceService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
 	return []types.HistoricalPricePoint{}, m.err
 }
 
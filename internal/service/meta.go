@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type responseMetaKey struct{}
+
+// ResponseMeta collects per-ticker details about how a request was answered,
+// such as which provider served it and how fresh the value is. Transports
+// attach one to the request context and read it back after the service call
+// returns.
+type ResponseMeta struct {
+	mu      sync.Mutex
+	tickers map[string]TickerMeta
+}
+
+// TickerMeta describes the answer given for one ticker
+type TickerMeta struct {
+	Source string
+	Stale  bool
+	AsOf   time.Time
+}
+
+// WithResponseMeta returns a context carrying a fresh ResponseMeta
+func WithResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{tickers: make(map[string]TickerMeta)}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+// ResponseMetaFromContext returns the ResponseMeta attached to ctx, if any
+func ResponseMetaFromContext(ctx context.Context) *ResponseMeta {
+	meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	return meta
+}
+
+// Ticker returns everything recorded for ticker
+func (m *ResponseMeta) Ticker(ticker string) TickerMeta {
+	if m == nil {
+		return TickerMeta{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tickers[ticker]
+}
+
+// Source returns the provider that answered for ticker
+func (m *ResponseMeta) Source(ticker string) string {
+	return m.Ticker(ticker).Source
+}
+
+// Sources returns a copy of the ticker to provider mapping
+func (m *ResponseMeta) Sources() map[string]string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sources := make(map[string]string, len(m.tickers))
+	for ticker, tm := range m.tickers {
+		if tm.Source != "" {
+			sources[ticker] = tm.Source
+		}
+	}
+	return sources
+}
+
+// StaleTickers returns the tickers answered with stale values
+func (m *ResponseMeta) StaleTickers() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stale []string
+	for ticker, tm := range m.tickers {
+		if tm.Stale {
+			stale = append(stale, ticker)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// update applies fn to the metadata recorded for ticker
+func (m *ResponseMeta) update(ticker string, fn func(*TickerMeta)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tm := m.tickers[ticker]
+	fn(&tm)
+	m.tickers[ticker] = tm
+}
+
+// recordSource notes the provider that answered for ticker. The first
+// recorded provider wins so nested registries report the innermost one.
+func recordSource(ctx context.Context, ticker, provider string) {
+	meta := ResponseMetaFromContext(ctx)
+	if meta == nil {
+		return
+	}
+	meta.update(ticker, func(tm *TickerMeta) {
+		if tm.Source == "" {
+			tm.Source = provider
+		}
+	})
+}
+
+// recordFreshness notes whether the value for ticker was stale and when it
+// was fetched from upstream. As with sources, the first record wins.
+func recordFreshness(ctx context.Context, ticker string, stale bool, asOf time.Time) {
+	meta := ResponseMetaFromContext(ctx)
+	if meta == nil {
+		return
+	}
+	meta.update(ticker, func(tm *TickerMeta) {
+		if tm.AsOf.IsZero() {
+			tm.Stale = stale
+			tm.AsOf = asOf
+		}
+	})
+}
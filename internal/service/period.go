@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// Period is the span daily bars are resampled to
+type Period string
+
+// Supported resampling periods
+const (
+	PeriodDaily     Period = "daily"
+	PeriodWeekly    Period = "weekly"
+	PeriodMonthly   Period = "monthly"
+	PeriodQuarterly Period = "quarterly"
+)
+
+// ParsePeriod parses a period name. An empty name means daily bars.
+func ParsePeriod(name string) (Period, error) {
+	switch period := Period(name); period {
+	case "":
+		return PeriodDaily, nil
+	case PeriodDaily, PeriodWeekly, PeriodMonthly, PeriodQuarterly:
+		return period, nil
+	default:
+		return "", apperror.InvalidArgument("unsupported period %q: use daily, weekly, monthly or quarterly", name)
+	}
+}
+
+// bucketStart returns the first day of the period containing day. Weeks
+// follow ISO 8601 and start on Monday.
+func (p Period) bucketStart(day time.Time) time.Time {
+	switch p {
+	case PeriodWeekly:
+		sinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -sinceMonday)
+	case PeriodMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case PeriodQuarterly:
+		firstMonth := (day.Month()-1)/3*3 + 1
+		return time.Date(day.Year(), firstMonth, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// ResampleBars aggregates daily bars into one bar per period, taking the
+// first open, highest high, lowest low and last close of each bucket.
+// Volumes and dividends are summed and split coefficients multiplied. Each
+// bar is dated with the first day of its period, so weekly bars carry the
+// Monday of their ISO week even when the week starts with a holiday.
+func ResampleBars(points []types.HistoricalPricePoint, period Period) ([]types.HistoricalPricePoint, error) {
+	if period == "" || period == PeriodDaily {
+		return points, nil
+	}
+
+	sorted := make([]types.HistoricalPricePoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	resampled := make([]types.HistoricalPricePoint, 0)
+	var current *types.HistoricalPricePoint
+	for _, p := range sorted {
+		day, err := time.Parse(history.DateLayout, p.Date)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resample bar dated %q: %w", p.Date, err)
+		}
+		label := period.bucketStart(day).Format(history.DateLayout)
+
+		if current == nil || current.Date != label {
+			p.Date = label
+			resampled = append(resampled, p)
+			current = &resampled[len(resampled)-1]
+			continue
+		}
+
+		current.High = math.Max(current.High, p.High)
+		current.Low = math.Min(current.Low, p.Low)
+		current.Close = p.Close
+		current.AdjustedClose = p.AdjustedClose
+		current.Volume += p.Volume
+		current.DividendAmount += p.DividendAmount
+		// Bars without corporate action data carry no coefficient
+		if p.SplitCoefficient > 0 {
+			if current.SplitCoefficient > 0 {
+				current.SplitCoefficient *= p.SplitCoefficient
+			} else {
+				current.SplitCoefficient = p.SplitCoefficient
+			}
+		}
+	}
+	return resampled, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+func TestResampleBars(t *testing.T) {
+	daily := []types.HistoricalPricePoint{
+		{Date: "2024-12-27", Open: 10, High: 12, Low: 9, Close: 11},
+		{Date: "2024-12-30", Open: 11, High: 15, Low: 10, Close: 14},
+		{Date: "2024-12-31", Open: 14, High: 14, Low: 8, Close: 9},
+		// ISO week 1 of 2025 starts on Monday 2024-12-30
+		{Date: "2025-01-02", Open: 9, High: 13, Low: 9, Close: 12},
+		{Date: "2025-04-01", Open: 20, High: 21, Low: 19, Close: 20},
+	}
+
+	tests := []struct {
+		name   string
+		period Period
+		want   []types.HistoricalPricePoint
+	}{
+		{
+			name:   "Weekly on ISO weeks",
+			period: PeriodWeekly,
+			want: []types.HistoricalPricePoint{
+				{Date: "2024-12-23", Open: 10, High: 12, Low: 9, Close: 11},
+				{Date: "2024-12-30", Open: 11, High: 15, Low: 8, Close: 12},
+				{Date: "2025-03-31", Open: 20, High: 21, Low: 19, Close: 20},
+			},
+		},
+		{
+			name:   "Monthly",
+			period: PeriodMonthly,
+			want: []types.HistoricalPricePoint{
+				{Date: "2024-12-01", Open: 10, High: 15, Low: 8, Close: 9},
+				{Date: "2025-01-01", Open: 9, High: 13, Low: 9, Close: 12},
+				{Date: "2025-04-01", Open: 20, High: 21, Low: 19, Close: 20},
+			},
+		},
+		{
+			name:   "Quarterly",
+			period: PeriodQuarterly,
+			want: []types.HistoricalPricePoint{
+				{Date: "2024-10-01", Open: 10, High: 15, Low: 8, Close: 9},
+				{Date: "2025-01-01", Open: 9, High: 13, Low: 9, Close: 12},
+				{Date: "2025-04-01", Open: 20, High: 21, Low: 19, Close: 20},
+			},
+		},
+		{
+			name:   "Daily is unchanged",
+			period: PeriodDaily,
+			want:   daily,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResampleBars(daily, tt.period)
+			if err != nil {
+				t.Fatalf("ResampleBars() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResampleBars() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("ResampleBars()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResampleBars_MockProvider(t *testing.T) {
+	daily, err := MockPriceHistoryFetcher(context.Background(), "AAPL", IntervalDaily, "", "")
+	if err != nil {
+		t.Fatalf("MockPriceHistoryFetcher() error = %v", err)
+	}
+
+	weekly, err := ResampleBars(daily, PeriodWeekly)
+	if err != nil {
+		t.Fatalf("ResampleBars() error = %v", err)
+	}
+
+	// The mock days run Monday to Friday of one ISO week
+	want := types.HistoricalPricePoint{
+		Date: "2024-01-01", Open: 145, High: 160, Low: 140, Close: 156,
+		Volume: 5550000, AdjustedClose: 156, SplitCoefficient: 1,
+	}
+	if len(weekly) != 1 || weekly[0] != want {
+		t.Errorf("ResampleBars() = %+v, want [%+v]", weekly, want)
+	}
+}
+
+func TestResampleBars_CorporateActions(t *testing.T) {
+	daily := []types.HistoricalPricePoint{
+		{Date: "2020-08-27", Open: 500, High: 510, Low: 495, Close: 500, Volume: 100, AdjustedClose: 124, SplitCoefficient: 1},
+		{Date: "2020-08-28", Open: 504, High: 505, Low: 498, Close: 499, Volume: 200, AdjustedClose: 123, DividendAmount: 0.82, SplitCoefficient: 1},
+		{Date: "2020-08-31", Open: 127, High: 131, Low: 126, Close: 129, Volume: 800, AdjustedClose: 128, SplitCoefficient: 4},
+		{Date: "2020-09-01", Open: 132, High: 134, Low: 130, Close: 134, Volume: 600, AdjustedClose: 133, SplitCoefficient: 1},
+	}
+
+	monthly, err := ResampleBars(daily, PeriodMonthly)
+	if err != nil {
+		t.Fatalf("ResampleBars() error = %v", err)
+	}
+
+	want := []types.HistoricalPricePoint{
+		{Date: "2020-08-01", Open: 500, High: 510, Low: 126, Close: 129, Volume: 1100, AdjustedClose: 128, DividendAmount: 0.82, SplitCoefficient: 4},
+		{Date: "2020-09-01", Open: 132, High: 134, Low: 130, Close: 134, Volume: 600, AdjustedClose: 133, SplitCoefficient: 1},
+	}
+	if len(monthly) != len(want) {
+		t.Fatalf("ResampleBars() = %+v, want %+v", monthly, want)
+	}
+	for i := range want {
+		if monthly[i] != want[i] {
+			t.Errorf("ResampleBars()[%d] = %+v, want %+v", i, monthly[i], want[i])
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	if got, err := ParsePeriod(""); err != nil || got != PeriodDaily {
+		t.Errorf("ParsePeriod(\"\") = %q, %v; want daily", got, err)
+	}
+	if got, err := ParsePeriod("quarterly"); err != nil || got != PeriodQuarterly {
+		t.Errorf("ParsePeriod(quarterly) = %q, %v", got, err)
+	}
+	if _, err := ParsePeriod("yearly"); err == nil {
+		t.Error("Expected error for unsupported period")
+	}
+}
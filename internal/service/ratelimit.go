@@ -0,0 +1,310 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+)
+
+// Priority orders callers queued on the upstream rate limiter
+type Priority int
+
+const (
+	PriorityBackground Priority = iota
+	PriorityInteractive
+)
+
+type priorityKey struct{}
+
+// WithPriority marks the upstream calls made with ctx as having priority p.
+// Calls without a priority are treated as interactive.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// ErrQuotaExhausted is matched by every QuotaExhaustedError
+var ErrQuotaExhausted = errors.New("upstream quota exhausted")
+
+// QuotaExhaustedError is returned when a call cannot get an upstream slot
+// before its context deadline
+type QuotaExhaustedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("upstream quota exhausted: retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaExhaustedError) Is(target error) bool {
+	return target == ErrQuotaExhausted || target == apperror.ErrRateLimited
+}
+
+// tokenBucket refills capacity tokens evenly over period
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(capacity int, period time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		rate:     float64(capacity) / period.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// timeUntil returns how long until n tokens are available
+func (b *tokenBucket) timeUntil(n float64) time.Duration {
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+type waiter struct {
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+// waitQueue is a heap of waiters, highest priority first and FIFO within
+// a priority
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
+
+// RateLimiter shares per-minute and per-day upstream budgets between all
+// callers. Callers that cannot be served immediately queue by priority.
+type RateLimiter struct {
+	mutex   sync.Mutex
+	buckets []*tokenBucket
+	queue   waitQueue
+	seq     uint64
+	timer   *time.Timer
+}
+
+// NewRateLimiter creates a limiter allowing perMinute and perDay calls.
+// A non-positive budget is not enforced; nil is returned when neither is.
+func NewRateLimiter(perMinute, perDay int) *RateLimiter {
+	var buckets []*tokenBucket
+	if perMinute > 0 {
+		buckets = append(buckets, newTokenBucket(perMinute, time.Minute))
+	}
+	if perDay > 0 {
+		buckets = append(buckets, newTokenBucket(perDay, 24*time.Hour))
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+	return newRateLimiter(buckets...)
+}
+
+func newRateLimiter(buckets ...*tokenBucket) *RateLimiter {
+	return &RateLimiter{buckets: buckets}
+}
+
+// Wait blocks until the caller may make one upstream call. It returns a
+// QuotaExhaustedError if that cannot happen before the context deadline.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	l.refill(time.Now())
+
+	priority := priorityFromContext(ctx)
+	if len(l.queue) == 0 && l.available(1) {
+		l.take()
+		l.mutex.Unlock()
+		return nil
+	}
+
+	estimate := l.estimate(priority)
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(estimate).After(deadline) {
+		l.mutex.Unlock()
+		return &QuotaExhaustedError{RetryAfter: estimate}
+	}
+
+	l.seq++
+	w := &waiter{priority: priority, seq: l.seq, ready: make(chan struct{})}
+	heap.Push(&l.queue, w)
+	l.schedule()
+	l.mutex.Unlock()
+
+	defer markQueued(ctx)()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		select {
+		case <-w.ready:
+			// Granted while we were giving up; use the slot
+			return nil
+		default:
+		}
+
+		heap.Remove(&l.queue, w.index)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &QuotaExhaustedError{RetryAfter: l.timeUntil(1)}
+		}
+		return ctx.Err()
+	}
+}
+
+// Check reports, without taking a slot, whether the caller could get one
+// before its context deadline. It returns the QuotaExhaustedError Wait
+// would return if not.
+func (l *RateLimiter) Check(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	if estimate := l.estimate(priorityFromContext(ctx)); estimate > 0 && now.Add(estimate).After(deadline) {
+		return &QuotaExhaustedError{RetryAfter: estimate}
+	}
+	return nil
+}
+
+// nextSlot returns how long until the next upstream slot frees up
+func (l *RateLimiter) nextSlot() time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.refill(time.Now())
+	return l.timeUntil(1)
+}
+
+// estimate returns how long a caller at priority p would wait for a slot.
+// Everyone queued at its priority or higher is served first.
+func (l *RateLimiter) estimate(p Priority) time.Duration {
+	ahead := 0
+	for _, w := range l.queue {
+		if w.priority >= p {
+			ahead++
+		}
+	}
+	return l.timeUntil(float64(ahead + 1))
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	for _, b := range l.buckets {
+		b.refill(now)
+	}
+}
+
+func (l *RateLimiter) available(n float64) bool {
+	for _, b := range l.buckets {
+		if b.tokens < n {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *RateLimiter) take() {
+	for _, b := range l.buckets {
+		b.tokens--
+	}
+}
+
+func (l *RateLimiter) timeUntil(n float64) time.Duration {
+	var wait time.Duration
+	for _, b := range l.buckets {
+		if d := b.timeUntil(n); d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+// dispatch hands out available tokens to queued waiters in priority order
+func (l *RateLimiter) dispatch() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.timer = nil
+	l.refill(time.Now())
+	for len(l.queue) > 0 && l.available(1) {
+		w := heap.Pop(&l.queue).(*waiter)
+		l.take()
+		close(w.ready)
+	}
+	l.schedule()
+}
+
+// schedule arms a timer for when the next queued waiter can be served
+func (l *RateLimiter) schedule() {
+	if len(l.queue) == 0 || l.timer != nil {
+		return
+	}
+	l.timer = time.AfterFunc(l.timeUntil(1), l.dispatch)
+}
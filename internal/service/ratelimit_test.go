@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AllowsBurstUpToBudget(t *testing.T) {
+	limiter := NewRateLimiter(3, 0)
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() call %d error = %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+	var quotaErr *QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Wait() error = %v, want QuotaExhaustedError", err)
+	}
+	if !errors.Is(err, ErrQuotaExhausted) {
+		t.Error("Expected error to match ErrQuotaExhausted")
+	}
+	if quotaErr.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want positive", quotaErr.RetryAfter)
+	}
+}
+
+func TestRateLimiter_DailyBudget(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Wait() error = %v, want ErrQuotaExhausted", err)
+	}
+}
+
+func TestRateLimiter_InteractiveBeforeBackground(t *testing.T) {
+	limiter := newRateLimiter(newTokenBucket(1, 100*time.Millisecond))
+
+	// Drain the only token so both callers have to queue
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	order := make(chan Priority, 3)
+	wait := func(p Priority) {
+		if err := limiter.Wait(WithPriority(context.Background(), p)); err != nil {
+			t.Errorf("Wait() error = %v", err)
+		}
+		order <- p
+	}
+
+	go wait(PriorityBackground)
+	time.Sleep(10 * time.Millisecond)
+	go wait(PriorityBackground)
+	time.Sleep(10 * time.Millisecond)
+	go wait(PriorityInteractive)
+
+	if first := <-order; first != PriorityInteractive {
+		t.Errorf("First served priority = %v, want interactive", first)
+	}
+	<-order
+	<-order
+}
+
+func TestRateLimiter_CancelledWaiterLeavesQueue(t *testing.T) {
+	limiter := newRateLimiter(newTokenBucket(1, 50*time.Millisecond))
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() after cancellation error = %v", err)
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	limiter := NewRateLimiter(0, 0)
+	if limiter != nil {
+		t.Fatal("Expected nil limiter when no budget is set")
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() on nil limiter error = %v", err)
+	}
+}
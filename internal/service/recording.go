@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// TickRecorder persists observed prices
+type TickRecorder interface {
+	Record(ticks ...ticks.Tick) error
+}
+
+// RecordingService records every price returned by the wrapped service as
+// a tick. A cached value served again is recorded only once, stamped with
+// the time it was fetched upstream.
+type RecordingService struct {
+	next     PriceService
+	recorder TickRecorder
+	mutex    sync.Mutex
+	lastAsOf map[string]time.Time
+}
+
+func (s *RecordingService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	ctx, meta := ensureResponseMeta(ctx)
+	price, err := s.next.FetchPrice(ctx, ticker)
+	if err == nil {
+		s.record(meta, map[string]float64{ticker: price})
+	}
+	return price, err
+}
+
+func (s *RecordingService) FetchPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
+	ctx, meta := ensureResponseMeta(ctx)
+	prices, err := s.next.FetchPrices(ctx, tickers)
+	if len(prices) > 0 {
+		s.record(meta, prices)
+	}
+	return prices, err
+}
+
+func (s *RecordingService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	ctx, meta := ensureResponseMeta(ctx)
+	quote, err := s.next.FetchQuote(ctx, ticker)
+	if err == nil {
+		s.record(meta, map[string]float64{ticker: quote.Price})
+	}
+	return quote, err
+}
+
+func (s *RecordingService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	return s.next.SearchSymbols(ctx, query)
+}
+
+func (s *RecordingService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	return s.next.FetchSymbol(ctx, ticker)
+}
+
+func (s *RecordingService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	return s.next.FetchPriceHistory(ctx, ticker, interval, fromDate, toDate)
+}
+
+// record appends the observed prices, skipping values already recorded
+func (s *RecordingService) record(meta *ResponseMeta, prices map[string]float64) {
+	now := time.Now()
+	observed := make([]ticks.Tick, 0, len(prices))
+
+	s.mutex.Lock()
+	for ticker, price := range prices {
+		tm := meta.Ticker(ticker)
+		if !tm.AsOf.IsZero() {
+			if tm.AsOf.Equal(s.lastAsOf[ticker]) {
+				continue
+			}
+			s.lastAsOf[ticker] = tm.AsOf
+		}
+
+		at := tm.AsOf
+		if at.IsZero() {
+			at = now
+		}
+		observed = append(observed, ticks.Tick{Ticker: ticker, Price: price, Source: tm.Source, Time: at, Stale: tm.Stale})
+	}
+	s.mutex.Unlock()
+
+	if len(observed) == 0 {
+		return
+	}
+	if err := s.recorder.Record(observed...); err != nil {
+		logrus.WithError(err).Warn("Failed to record ticks")
+	}
+}
+
+// ensureResponseMeta returns the ResponseMeta already attached to ctx, or
+// attaches a new one
+func ensureResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	if meta := ResponseMetaFromContext(ctx); meta != nil {
+		return ctx, meta
+	}
+	return WithResponseMeta(ctx)
+}
+
+func NewRecordingService(next PriceService, recorder TickRecorder) PriceService {
+	return &RecordingService{
+		next:     next,
+		recorder: recorder,
+		lastAsOf: make(map[string]time.Time),
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/ticks"
+)
+
+type memoryRecorder struct {
+	mutex sync.Mutex
+	ticks []ticks.Tick
+}
+
+func (r *memoryRecorder) Record(observed ...ticks.Tick) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ticks = append(r.ticks, observed...)
+	return nil
+}
+
+func (r *memoryRecorder) recorded() []ticks.Tick {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]ticks.Tick(nil), r.ticks...)
+}
+
+// freshnessService reports a fixed fetch time for every price, as a
+// caching provider would
+type freshnessService struct {
+	mockPriceService
+	asOf time.Time
+}
+
+func (s *freshnessService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	recordSource(ctx, ticker, "cached")
+	recordFreshness(ctx, ticker, false, s.asOf)
+	return s.mockPriceService.FetchPrice(ctx, ticker)
+}
+
+func TestRecordingService_RecordsPrices(t *testing.T) {
+	recorder := &memoryRecorder{}
+	registry := NewProviderRegistry(0)
+	registry.Register(ProviderMock, &priceService{})
+	svc := NewRecordingService(registry, recorder)
+
+	if _, err := svc.FetchPrice(context.Background(), "AAPL"); err != nil {
+		t.Fatalf("FetchPrice() error = %v", err)
+	}
+	if _, err := svc.FetchPrices(context.Background(), []string{"MSFT", "GOOGL"}); err != nil {
+		t.Fatalf("FetchPrices() error = %v", err)
+	}
+	if _, err := svc.FetchPrice(context.Background(), "UNKNOWN"); err == nil {
+		t.Fatal("Expected error for unknown ticker")
+	}
+
+	got := recorder.recorded()
+	if len(got) != 3 {
+		t.Fatalf("Recorded %d ticks, want 3", len(got))
+	}
+	if got[0].Ticker != "AAPL" || got[0].Price != 150.0 || got[0].Source != ProviderMock || got[0].Time.IsZero() {
+		t.Errorf("Recorded tick = %+v, want AAPL at 150 from mock", got[0])
+	}
+}
+
+func TestRecordingService_SkipsRepeatedCachedValues(t *testing.T) {
+	recorder := &memoryRecorder{}
+	asOf := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	next := &freshnessService{mockPriceService: mockPriceService{price: 150.0}, asOf: asOf}
+	svc := NewRecordingService(next, recorder)
+
+	for i := 0; i < 3; i++ {
+		svc.FetchPrice(context.Background(), "AAPL")
+	}
+
+	got := recorder.recorded()
+	if len(got) != 1 {
+		t.Fatalf("Recorded %d ticks, want 1", len(got))
+	}
+	if !got[0].Time.Equal(asOf) || got[0].Source != "cached" {
+		t.Errorf("Recorded tick = %+v, want fetch time %v from cached", got[0], asOf)
+	}
+}
+
+func TestRecordingService_KeepsCallerResponseMeta(t *testing.T) {
+	recorder := &memoryRecorder{}
+	next := &freshnessService{mockPriceService: mockPriceService{err: errors.New("upstream down")}}
+	svc := NewRecordingService(next, recorder)
+
+	ctx, meta := WithResponseMeta(context.Background())
+	svc.FetchPrice(ctx, "AAPL")
+
+	if meta.Source("AAPL") != "cached" {
+		t.Errorf("Source() = %q, want metadata recorded on the caller's ResponseMeta", meta.Source("AAPL"))
+	}
+	if len(recorder.recorded()) != 0 {
+		t.Error("Expected failed fetches not to be recorded")
+	}
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/cache"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// Names of the built-in price providers
+const (
+	ProviderMock         = "mock"
+	ProviderAlphaVantage = "alphavantage"
+	ProviderBinance      = "binance"
+)
+
+const defaultProviderTimeout = 10 * time.Second
+
+// ProviderRegistry holds named price providers and answers each request from
+// the first provider, in priority order, that returns a usable result.
+// A provider that errors, times out or returns an empty quote is skipped.
+type ProviderRegistry struct {
+	providers map[string]PriceService
+	order     []string
+	mutex     sync.RWMutex
+	timeout   time.Duration
+}
+
+type namedProvider struct {
+	name string
+	svc  PriceService
+}
+
+// NewProviderRegistry creates an empty registry. Each provider call is
+// bounded by timeout; a non-positive timeout uses the default.
+func NewProviderRegistry(timeout time.Duration) *ProviderRegistry {
+	if timeout <= 0 {
+		timeout = defaultProviderTimeout
+	}
+	return &ProviderRegistry{
+		providers: make(map[string]PriceService),
+		timeout:   timeout,
+	}
+}
+
+// ProviderOptions holds the resources shared by the built-in providers.
+// Providers fall back to in-memory defaults for nil fields.
+type ProviderOptions struct {
+	// Cache stores upstream answers
+	Cache cache.Cache
+	// History persists daily bars between restarts
+	History history.Store
+	// AlphaVantage configures the Alpha Vantage provider; nil uses
+	// DefaultAlphaVantageOptions
+	AlphaVantage *AlphaVantageOptions
+	// BinanceBaseURL is the Binance-compatible API the binance provider
+	// calls; empty uses Binance itself
+	BinanceBaseURL string
+}
+
+// NewProviderByName creates one of the built-in providers
+func NewProviderByName(name string, opts ProviderOptions) (PriceService, error) {
+	switch name {
+	case ProviderMock:
+		return &priceService{}, nil
+	case ProviderAlphaVantage:
+		return NewAlphaVantageServiceWithOptions(opts), nil
+	case ProviderBinance:
+		return NewBinanceService(opts.BinanceBaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown price provider: %s", name)
+	}
+}
+
+// NewFailoverPriceService builds a registry of built-in providers tried in
+// the order given by names
+func NewFailoverPriceService(names []string, timeout time.Duration, opts ProviderOptions) (*ProviderRegistry, error) {
+	registry := NewProviderRegistry(timeout)
+	for _, name := range names {
+		provider, err := NewProviderByName(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Register(name, provider); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register adds a provider under name. Providers are tried in registration
+// order unless SetPriority is called.
+func (r *ProviderRegistry) Register(name string, provider PriceService) error {
+	if name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if provider == nil {
+		return fmt.Errorf("provider %s is nil", name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("provider already registered: %s", name)
+	}
+
+	r.providers[name] = provider
+	r.order = append(r.order, name)
+	return nil
+}
+
+// SetPriority replaces the order in which providers are tried. Providers
+// left out of names are not consulted.
+func (r *ProviderRegistry) SetPriority(names ...string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, exists := r.providers[name]; !exists {
+			return fmt.Errorf("unknown price provider: %s", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("provider listed twice: %s", name)
+		}
+		seen[name] = true
+	}
+
+	r.order = append([]string(nil), names...)
+	return nil
+}
+
+// Providers returns the provider names in priority order
+func (r *ProviderRegistry) Providers() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]string(nil), r.order...)
+}
+
+// snapshot returns the providers to try, in priority order
+func (r *ProviderRegistry) snapshot() []namedProvider {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	providers := make([]namedProvider, 0, len(r.order))
+	for _, name := range r.order {
+		providers = append(providers, namedProvider{name: name, svc: r.providers[name]})
+	}
+	return providers
+}
+
+// FetchPrice returns the price from the first provider that answers
+func (r *ProviderRegistry) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return 0, fmt.Errorf("no price providers registered")
+	}
+
+	var errs []error
+	for _, p := range providers {
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		price, err := p.svc.FetchPrice(providerCtx, ticker)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if price <= 0 {
+			errs = append(errs, apperror.NotFound("%s: empty quote for %s", p.name, ticker))
+			continue
+		}
+
+		recordSource(ctx, ticker, p.name)
+		return price, nil
+	}
+
+	return 0, fmt.Errorf("all providers failed for %s: %w", ticker, errors.Join(errs...))
+}
+
+// FetchPrices asks each provider in turn for the tickers still unanswered
+func (r *ProviderRegistry) FetchPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no price providers registered")
+	}
+
+	results := make(map[string]float64, len(tickers))
+	remaining := tickers
+	var errs []error
+
+	for _, p := range providers {
+		if len(remaining) == 0 || ctx.Err() != nil {
+			break
+		}
+
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		prices, err := p.svc.FetchPrices(providerCtx, remaining)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+		}
+
+		var missing []string
+		for _, ticker := range remaining {
+			if price, ok := prices[ticker]; ok && price > 0 {
+				results[ticker] = price
+				recordSource(ctx, ticker, p.name)
+				continue
+			}
+			missing = append(missing, ticker)
+		}
+		remaining = missing
+	}
+
+	if len(results) == 0 {
+		if len(errs) == 0 {
+			errs = append(errs, ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to fetch prices for any ticker: %w", errors.Join(errs...))
+	}
+
+	return results, nil
+}
+
+// FetchQuote returns the quote from the first provider that answers
+func (r *ProviderRegistry) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return types.Quote{}, fmt.Errorf("no price providers registered")
+	}
+
+	var errs []error
+	for _, p := range providers {
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		quote, err := p.svc.FetchQuote(providerCtx, ticker)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if quote.Price <= 0 {
+			errs = append(errs, apperror.NotFound("%s: empty quote for %s", p.name, ticker))
+			continue
+		}
+
+		recordSource(ctx, ticker, p.name)
+		return quote, nil
+	}
+
+	return types.Quote{}, fmt.Errorf("all providers failed for %s: %w", ticker, errors.Join(errs...))
+}
+
+// SearchSymbols returns the matches from the first provider that finds any.
+// If every provider answers with no matches, the empty result is returned
+// rather than an error.
+func (r *ProviderRegistry) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no price providers registered")
+	}
+
+	var errs []error
+	emptyFrom := ""
+	for _, p := range providers {
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		matches, err := p.svc.SearchSymbols(providerCtx, query)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if len(matches) == 0 {
+			if emptyFrom == "" {
+				emptyFrom = p.name
+			}
+			continue
+		}
+
+		recordSource(ctx, query, p.name)
+		return matches, nil
+	}
+
+	if emptyFrom != "" {
+		recordSource(ctx, query, emptyFrom)
+		return []types.SymbolInfo{}, nil
+	}
+
+	return nil, fmt.Errorf("all providers failed to search %q: %w", query, errors.Join(errs...))
+}
+
+// FetchSymbol returns the metadata from the first provider that knows ticker
+func (r *ProviderRegistry) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return types.SymbolInfo{}, fmt.Errorf("no price providers registered")
+	}
+
+	var errs []error
+	for _, p := range providers {
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		info, err := p.svc.FetchSymbol(providerCtx, ticker)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		recordSource(ctx, ticker, p.name)
+		return info, nil
+	}
+
+	return types.SymbolInfo{}, fmt.Errorf("all providers failed for %s: %w", ticker, errors.Join(errs...))
+}
+
+// FetchPriceHistory returns the history from the first provider that has
+// data for the range. If every provider answers with no data, the empty
+// result is returned rather than an error.
+func (r *ProviderRegistry) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	providers := r.snapshot()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no price providers registered")
+	}
+
+	var errs []error
+	emptyFrom := ""
+	for _, p := range providers {
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		history, err := p.svc.FetchPriceHistory(providerCtx, ticker, interval, fromDate, toDate)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if len(history) == 0 {
+			if emptyFrom == "" {
+				emptyFrom = p.name
+			}
+			continue
+		}
+
+		recordSource(ctx, ticker, p.name)
+		return history, nil
+	}
+
+	if emptyFrom != "" {
+		recordSource(ctx, ticker, emptyFrom)
+		return []types.HistoricalPricePoint{}, nil
+	}
+
+	return nil, fmt.Errorf("all providers failed for %s: %w", ticker, errors.Join(errs...))
+}
+
+// FetchRate returns the exchange rate from the first provider offering
+// rates that answers
+func (r *ProviderRegistry) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	var errs []error
+	for _, p := range r.snapshot() {
+		rates, ok := p.svc.(RateService)
+		if !ok {
+			continue
+		}
+
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		rate, err := rates.FetchRate(providerCtx, from, to)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		return rate, nil
+	}
+
+	if len(errs) == 0 {
+		return 0, apperror.NotFound("no provider offers exchange rates")
+	}
+	return 0, fmt.Errorf("all providers failed for %s/%s: %w", from, to, errors.Join(errs...))
+}
+
+// FetchRateHistory returns the daily rates from the first provider offering
+// rates that has any for the range
+func (r *ProviderRegistry) FetchRateHistory(ctx context.Context, from, to, fromDate, toDate string) (map[string]float64, error) {
+	var errs []error
+	for _, p := range r.snapshot() {
+		rates, ok := p.svc.(RateService)
+		if !ok {
+			continue
+		}
+
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		history, err := rates.FetchRateHistory(providerCtx, from, to, fromDate, toDate)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if len(history) == 0 {
+			errs = append(errs, apperror.NotFound("%s: no %s/%s rates from %s to %s", p.name, from, to, fromDate, toDate))
+			continue
+		}
+		return history, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, apperror.NotFound("no provider offers exchange rates")
+	}
+	return nil, fmt.Errorf("all providers failed for %s/%s: %w", from, to, errors.Join(errs...))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+type slowPriceService struct {
+	delay time.Duration
+}
+
+func (s *slowPriceService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
+	select {
+	case <-time.After(s.delay):
+		return 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (s *slowPriceService) FetchPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *slowPriceService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	return types.Quote{}, errors.New("not implemented")
+}
+
+func (s *slowPriceService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *slowPriceService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	return types.SymbolInfo{}, errors.New("not implemented")
+}
+
+func (s *slowPriceService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestProviderRegistry_FetchPriceFailover(t *testing.T) {
+	tests := []struct {
+		name       string
+		primary    PriceService
+		wantPrice  float64
+		wantSource string
+	}{
+		{
+			name:       "Primary answers",
+			primary:    &mockPriceService{price: 100.0},
+			wantPrice:  100.0,
+			wantSource: "primary",
+		},
+		{
+			name:       "Primary errors",
+			primary:    &mockPriceService{err: errors.New("upstream down")},
+			wantPrice:  150.0,
+			wantSource: "backup",
+		},
+		{
+			name:       "Primary returns empty quote",
+			primary:    &mockPriceService{price: 0},
+			wantPrice:  150.0,
+			wantSource: "backup",
+		},
+		{
+			name:       "Primary times out",
+			primary:    &slowPriceService{delay: time.Second},
+			wantPrice:  150.0,
+			wantSource: "backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewProviderRegistry(50 * time.Millisecond)
+			if err := registry.Register("primary", tt.primary); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if err := registry.Register("backup", &priceService{}); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			ctx, meta := WithResponseMeta(context.Background())
+			got, err := registry.FetchPrice(ctx, "AAPL")
+			if err != nil {
+				t.Fatalf("FetchPrice() error = %v", err)
+			}
+			if got != tt.wantPrice {
+				t.Errorf("FetchPrice() = %v, want %v", got, tt.wantPrice)
+			}
+			if source := meta.Source("AAPL"); source != tt.wantSource {
+				t.Errorf("Source() = %v, want %v", source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestProviderRegistry_AllProvidersFail(t *testing.T) {
+	registry := NewProviderRegistry(time.Second)
+	registry.Register("first", &mockPriceService{err: errors.New("first down")})
+	registry.Register("second", &mockPriceService{err: errors.New("second down")})
+
+	if _, err := registry.FetchPrice(context.Background(), "AAPL"); err == nil {
+		t.Error("Expected error when every provider fails")
+	}
+}
+
+func TestProviderRegistry_FetchPricesFillsGaps(t *testing.T) {
+	registry := NewProviderRegistry(time.Second)
+	registry.Register("partial", &mockPriceService{prices: map[string]float64{"AAPL": 101.0}})
+	registry.Register("mock", &priceService{})
+
+	ctx, meta := WithResponseMeta(context.Background())
+	prices, err := registry.FetchPrices(ctx, []string{"AAPL", "MSFT"})
+	if err != nil {
+		t.Fatalf("FetchPrices() error = %v", err)
+	}
+
+	if prices["AAPL"] != 101.0 || prices["MSFT"] != 300.0 {
+		t.Errorf("FetchPrices() = %v, want AAPL=101 MSFT=300", prices)
+	}
+
+	sources := meta.Sources()
+	if sources["AAPL"] != "partial" || sources["MSFT"] != "mock" {
+		t.Errorf("Sources() = %v, want AAPL=partial MSFT=mock", sources)
+	}
+}
+
+func TestProviderRegistry_SetPriority(t *testing.T) {
+	registry := NewProviderRegistry(time.Second)
+	registry.Register("first", &mockPriceService{price: 1.0})
+	registry.Register("second", &mockPriceService{price: 2.0})
+
+	if err := registry.SetPriority("second", "first"); err != nil {
+		t.Fatalf("SetPriority() error = %v", err)
+	}
+
+	got, err := registry.FetchPrice(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("FetchPrice() error = %v", err)
+	}
+	if got != 2.0 {
+		t.Errorf("FetchPrice() = %v, want 2.0", got)
+	}
+
+	if err := registry.SetPriority("unknown"); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+	if err := registry.Register("first", &priceService{}); err == nil {
+		t.Error("Expected error for duplicate provider")
+	}
+}
+
+func TestNewFailoverPriceService(t *testing.T) {
+	registry, err := NewFailoverPriceService([]string{ProviderAlphaVantage, ProviderMock}, 0, ProviderOptions{})
+	if err != nil {
+		t.Fatalf("NewFailoverPriceService() error = %v", err)
+	}
+
+	providers := registry.Providers()
+	if len(providers) != 2 || providers[0] != ProviderAlphaVantage || providers[1] != ProviderMock {
+		t.Errorf("Providers() = %v, want [alphavantage mock]", providers)
+	}
+
+	if _, err := NewFailoverPriceService([]string{"bogus"}, 0, ProviderOptions{}); err == nil {
+		t.Error("Expected error for unknown provider name")
+	}
+}
+
+func TestProviderRegistry_FetchRate(t *testing.T) {
+	// Providers without rates are skipped
+	registry := NewProviderRegistry(time.Second)
+	registry.Register("slow", &slowPriceService{})
+	registry.Register("mock", &priceService{})
+
+	rate, err := registry.FetchRate(context.Background(), "USD", "EUR")
+	if err != nil || rate != 0.92 {
+		t.Errorf("FetchRate() = %v, %v; want 0.92", rate, err)
+	}
+
+	rates, err := registry.FetchRateHistory(context.Background(), "USD", "EUR", "2024-01-01", "2024-01-05")
+	if err != nil || len(rates) != 5 {
+		t.Errorf("FetchRateHistory() = %v, %v; want 5 rates", rates, err)
+	}
+
+	empty := NewProviderRegistry(time.Second)
+	empty.Register("slow", &slowPriceService{})
+	if _, err := empty.FetchRate(context.Background(), "USD", "EUR"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Errorf("FetchRate() error = %v, want not found", err)
+	}
+}
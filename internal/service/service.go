@@ -2,16 +2,35 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"time"
 
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/internal/history"
+	"github.com/aliexe/ms-priceFetcher/internal/symbol"
 	"github.com/aliexe/ms-priceFetcher/pkg/types"
 )
 
 var priceMocks = map[string]float64{
-	"AAPL":  150.0,
-	"MSFT":  300.0,
-	"GOOGL": 2800.0,
+	"AAPL":    150.0,
+	"MSFT":    300.0,
+	"GOOGL":   2800.0,
+	"BRK.B":   410.0,
+	"SHOP.TO": 105.0,
+	"7203.T":  2650.0,
+	"BTC-USD": 43000.0,
+}
+
+// mockSymbol translates a ticker to the canonical form mock data is keyed
+// by. Tickers that do not parse are left as is and match nothing.
+func mockSymbol(ticker string) string {
+	if normalized, err := symbol.Normalize(ticker); err == nil {
+		return normalized
+	}
+	return ticker
 }
 
 type priceService struct{}
@@ -19,7 +38,10 @@ type priceService struct{}
 type PriceService interface {
 	FetchPrice(context.Context, string) (float64, error)
 	FetchPrices(context.Context, []string) (map[string]float64, error)
-	FetchPriceHistory(context.Context, string, string, string) ([]types.HistoricalPricePoint, error)
+	FetchQuote(context.Context, string) (types.Quote, error)
+	SearchSymbols(context.Context, string) ([]types.SymbolInfo, error)
+	FetchSymbol(context.Context, string) (types.SymbolInfo, error)
+	FetchPriceHistory(context.Context, string, Interval, string, string) ([]types.HistoricalPricePoint, error)
 }
 
 func (s *priceService) FetchPrice(ctx context.Context, ticker string) (float64, error) {
@@ -30,54 +52,161 @@ func (s *priceService) FetchPrices(ctx context.Context, tickers []string) (map[s
 	return MockBatchPriceFetcher(ctx, tickers)
 }
 
-func (s *priceService) FetchPriceHistory(ctx context.Context, ticker, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
-	return MockPriceHistoryFetcher(ctx, ticker, fromDate, toDate)
+func (s *priceService) FetchQuote(ctx context.Context, ticker string) (types.Quote, error) {
+	return MockQuoteFetcher(ctx, ticker)
+}
+
+func (s *priceService) SearchSymbols(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	return MockSymbolSearcher(ctx, query)
+}
+
+func (s *priceService) FetchSymbol(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	return MockSymbolFetcher(ctx, ticker)
+}
+
+func (s *priceService) FetchPriceHistory(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	return MockPriceHistoryFetcher(ctx, ticker, interval, fromDate, toDate)
 }
 
 func MockPriceFetcher(ctx context.Context, ticker string) (float64, error) {
-	price, ok := priceMocks[ticker]
+	price, ok := priceMocks[mockSymbol(ticker)]
 	if !ok {
-		return 0, fmt.Errorf("price not found for %s", ticker)
+		return 0, apperror.NotFound("price not found for %s", ticker)
 	}
 	return price, nil
 }
 
 func MockBatchPriceFetcher(ctx context.Context, tickers []string) (map[string]float64, error) {
 	results := make(map[string]float64)
-	var errors []error
+	var errs []error
 
 	for _, ticker := range tickers {
 		price, err := MockPriceFetcher(ctx, ticker)
 		if err != nil {
-			errors = append(errors, err)
+			errs = append(errs, err)
 			continue
 		}
 		results[ticker] = price
 	}
 
-	if len(results) == 0 && len(errors) > 0 {
-		return nil, fmt.Errorf("failed to fetch prices for any ticker: %v", errors)
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to fetch prices for any ticker: %w", errors.Join(errs...))
 	}
 
 	return results, nil
 }
 
-func MockPriceHistoryFetcher(ctx context.Context, ticker, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
-	if _, ok := priceMocks[ticker]; !ok {
-		return nil, fmt.Errorf("ticker not found: %s", ticker)
+// MockQuoteFetcher builds a quote around the mock price: a 1.2% gain on the
+// previous close, with the session's range spanning both and a volume that
+// falls as the share price rises
+func MockQuoteFetcher(ctx context.Context, ticker string) (types.Quote, error) {
+	price, err := MockPriceFetcher(ctx, ticker)
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	previousClose := roundCents(price / 1.012)
+	change := roundCents(price - previousClose)
+	return types.Quote{
+		Ticker:           mockSymbol(ticker),
+		Open:             roundCents(previousClose + change/4),
+		High:             roundCents(price + change/2),
+		Low:              roundCents(previousClose - change/4),
+		Price:            price,
+		Volume:           int64(1e9 / price),
+		LatestTradingDay: latestTradingDay(time.Now()).Format(history.DateLayout),
+		PreviousClose:    previousClose,
+		Change:           change,
+		ChangePercent:    math.Round(change/previousClose*1e4) / 100,
+	}, nil
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// latestTradingDay returns the last weekday on or before now in the market
+// time zone. Exchange holidays are not accounted for.
+func latestTradingDay(now time.Time) time.Time {
+	day := now.In(marketLocation)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, marketLocation)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+func MockPriceHistoryFetcher(ctx context.Context, ticker string, interval Interval, fromDate, toDate string) ([]types.HistoricalPricePoint, error) {
+	basePrice, ok := priceMocks[mockSymbol(ticker)]
+	if !ok {
+		return nil, apperror.NotFound("ticker not found: %s", ticker)
 	}
 
 	// Generate mock historical data
-	basePrice := priceMocks[ticker]
 	mockData := []types.HistoricalPricePoint{
-		{Date: "2024-01-01", Open: basePrice - 5, High: basePrice + 5, Low: basePrice - 10, Close: basePrice - 2},
-		{Date: "2024-01-02", Open: basePrice - 2, High: basePrice + 3, Low: basePrice - 5, Close: basePrice + 1},
-		{Date: "2024-01-03", Open: basePrice + 1, High: basePrice + 8, Low: basePrice - 3, Close: basePrice + 5},
-		{Date: "2024-01-04", Open: basePrice + 5, High: basePrice + 10, Low: basePrice, Close: basePrice + 3},
-		{Date: "2024-01-05", Open: basePrice + 3, High: basePrice + 7, Low: basePrice + 1, Close: basePrice + 6},
+		{Date: "2024-01-01", Open: basePrice - 5, High: basePrice + 5, Low: basePrice - 10, Close: basePrice - 2, Volume: 1200000},
+		{Date: "2024-01-02", Open: basePrice - 2, High: basePrice + 3, Low: basePrice - 5, Close: basePrice + 1, Volume: 950000},
+		{Date: "2024-01-03", Open: basePrice + 1, High: basePrice + 8, Low: basePrice - 3, Close: basePrice + 5, Volume: 1430000},
+		{Date: "2024-01-04", Open: basePrice + 5, High: basePrice + 10, Low: basePrice, Close: basePrice + 3, Volume: 1100000},
+		{Date: "2024-01-05", Open: basePrice + 3, High: basePrice + 7, Low: basePrice + 1, Close: basePrice + 6, Volume: 870000},
+	}
+	// No splits or dividends fall within the mock range
+	for i := range mockData {
+		mockData[i].AdjustedClose = mockData[i].Close
+		mockData[i].SplitCoefficient = 1
 	}
 
-	return mockData, nil
+	if interval.Intraday() {
+		var intraday []types.HistoricalPricePoint
+		for _, day := range mockData {
+			intraday = append(intraday, mockIntradayBars(day, interval)...)
+		}
+		mockData = intraday
+	}
+
+	return filterBars(mockData, interval, fromDate, toDate)
+}
+
+// mockIntradayBars splits a daily bar into bars covering the 09:30-16:00
+// session, drifting from the day's open to its close within its range and
+// sharing out its volume
+func mockIntradayBars(day types.HistoricalPricePoint, interval Interval) []types.HistoricalPricePoint {
+	date, err := time.ParseInLocation(history.DateLayout, day.Date, marketLocation)
+	if err != nil {
+		return nil
+	}
+	open := date.Add(9*time.Hour + 30*time.Minute)
+	closing := date.Add(16 * time.Hour)
+	count := int(closing.Sub(open) / interval.Duration())
+
+	bars := make([]types.HistoricalPricePoint, 0, count)
+	last := day.Open
+	for i := 0; i < count; i++ {
+		progress := float64(i+1) / float64(count)
+		next := day.Open + (day.Close-day.Open)*progress
+		// Swing around the trend without leaving the day's range
+		next += (day.High - day.Low) / 4 * math.Sin(progress*2*math.Pi)
+		next = math.Max(day.Low, math.Min(day.High, next))
+		if i == count-1 {
+			next = day.Close
+		}
+
+		volume := day.Volume / int64(count)
+		if i == count-1 {
+			volume = day.Volume - volume*int64(count-1)
+		}
+
+		bars = append(bars, types.HistoricalPricePoint{
+			Date:   open.Add(time.Duration(i) * interval.Duration()).Format(time.RFC3339),
+			Open:   last,
+			High:   math.Max(last, next),
+			Low:    math.Min(last, next),
+			Close:  next,
+			Volume: volume,
+		})
+		last = next
+	}
+	return bars
 }
 
 // NewPriceService creates a price service based on environment configuration
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestMockPriceFetcher(t *testing.T) {
@@ -147,4 +149,48 @@ func TestNewPriceService(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestMockQuoteFetcher(t *testing.T) {
+	quote, err := MockQuoteFetcher(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("MockQuoteFetcher() error = %v", err)
+	}
+
+	if quote.Price != 150.0 || quote.Ticker != "AAPL" {
+		t.Errorf("MockQuoteFetcher() = %+v, want AAPL at 150", quote)
+	}
+	if quote.Low > quote.Open || quote.Low > quote.Price || quote.High < quote.Open || quote.High < quote.Price {
+		t.Errorf("MockQuoteFetcher() range %v-%v does not span open %v and price %v", quote.Low, quote.High, quote.Open, quote.Price)
+	}
+	if math.Abs(quote.PreviousClose+quote.Change-quote.Price) > 0.005 || quote.ChangePercent != 1.2 {
+		t.Errorf("MockQuoteFetcher() change = %v (%v%%) from %v, want a 1.2%% gain", quote.Change, quote.ChangePercent, quote.PreviousClose)
+	}
+	if quote.Volume <= 0 {
+		t.Errorf("MockQuoteFetcher() volume = %d, want positive", quote.Volume)
+	}
+
+	if _, err := MockQuoteFetcher(context.Background(), "INVALID"); err == nil {
+		t.Error("Expected error for unknown ticker")
+	}
+}
+
+func TestLatestTradingDay(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{now: time.Date(2024, 1, 5, 15, 0, 0, 0, time.UTC), want: "2024-01-05"},
+		// Saturday and Sunday fall back to Friday
+		{now: time.Date(2024, 1, 6, 15, 0, 0, 0, time.UTC), want: "2024-01-05"},
+		{now: time.Date(2024, 1, 7, 15, 0, 0, 0, time.UTC), want: "2024-01-05"},
+		// Just after midnight UTC on Tuesday is still Monday in New York
+		{now: time.Date(2024, 1, 9, 2, 0, 0, 0, time.UTC), want: "2024-01-08"},
+	}
+
+	for _, tt := range tests {
+		if got := latestTradingDay(tt.now).Format("2006-01-02"); got != tt.want {
+			t.Errorf("latestTradingDay(%v) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
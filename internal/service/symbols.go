@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+	"github.com/aliexe/ms-priceFetcher/pkg/types"
+)
+
+// symbolFixtures are the symbols known to the mock provider
+var symbolFixtures = []types.SymbolInfo{
+	{Symbol: "AAPL", Name: "Apple Inc", Exchange: "NASDAQ", Type: "Equity", Region: "United States", Currency: "USD"},
+	{Symbol: "MSFT", Name: "Microsoft Corporation", Exchange: "NASDAQ", Type: "Equity", Region: "United States", Currency: "USD"},
+	{Symbol: "GOOGL", Name: "Alphabet Inc - Class A", Exchange: "NASDAQ", Type: "Equity", Region: "United States", Currency: "USD"},
+	{Symbol: "BRK.B", Name: "Berkshire Hathaway Inc - Class B", Exchange: "NYSE", Type: "Equity", Region: "United States", Currency: "USD"},
+	{Symbol: "SHOP.TO", Name: "Shopify Inc", Exchange: "TSX", Type: "Equity", Region: "Canada", Currency: "CAD"},
+	{Symbol: "7203.T", Name: "Toyota Motor Corp", Exchange: "TSE", Type: "Equity", Region: "Japan", Currency: "JPY"},
+	{Symbol: "BTC-USD", Name: "Bitcoin USD", Type: "Crypto", Region: "Global", Currency: "USD"},
+}
+
+// MockSymbolSearcher matches query against the fixture symbols and names,
+// best match first
+func MockSymbolSearcher(ctx context.Context, query string) ([]types.SymbolInfo, error) {
+	matches := make([]types.SymbolInfo, 0)
+	for _, info := range symbolFixtures {
+		if score := matchScore(query, info); score > 0 {
+			info.MatchScore = score
+			matches = append(matches, info)
+		}
+	}
+	sortMatches(matches)
+	return matches, nil
+}
+
+// MockSymbolFetcher returns the fixture for ticker
+func MockSymbolFetcher(ctx context.Context, ticker string) (types.SymbolInfo, error) {
+	for _, info := range symbolFixtures {
+		if info.Symbol == mockSymbol(ticker) {
+			return info, nil
+		}
+	}
+	return types.SymbolInfo{}, apperror.NotFound("unknown symbol: %s", ticker)
+}
+
+// matchScore rates how well query matches a symbol, from 0 for no match to
+// 1 for the exact symbol. Symbol prefixes rank above name matches.
+func matchScore(query string, info types.SymbolInfo) float64 {
+	q := strings.ToLower(strings.TrimSpace(query))
+	symbol := strings.ToLower(info.Symbol)
+	name := strings.ToLower(info.Name)
+
+	var score float64
+	switch {
+	case q == "":
+		return 0
+	case q == symbol:
+		return 1
+	case strings.HasPrefix(symbol, q):
+		score = 0.5 + 0.5*float64(len(q))/float64(len(symbol))
+	case strings.Contains(name, q):
+		score = float64(len(q)) / float64(len(name))
+	}
+	return math.Round(score*1e4) / 1e4
+}
+
+// sortMatches orders search results by descending score, then by symbol
+func sortMatches(matches []types.SymbolInfo) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].MatchScore != matches[j].MatchScore {
+			return matches[i].MatchScore > matches[j].MatchScore
+		}
+		return matches[i].Symbol < matches[j].Symbol
+	})
+}
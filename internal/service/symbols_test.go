@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMockSymbolSearcher(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "Exact symbol", query: "msft", want: []string{"MSFT"}},
+		{name: "Symbol prefix", query: "GOO", want: []string{"GOOGL"}},
+		{name: "Company name", query: "apple", want: []string{"AAPL"}},
+		// Name matches rank by how much of the name the query covers
+		{name: "Symbol prefix ranks above name", query: "a", want: []string{"AAPL", "7203.T", "MSFT", "GOOGL", "BRK.B"}},
+		{name: "No match", query: "tesla", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MockSymbolSearcher(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("MockSymbolSearcher() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("MockSymbolSearcher(%q) = %+v, want %v", tt.query, got, tt.want)
+			}
+			for i, symbol := range tt.want {
+				if got[i].Symbol != symbol || got[i].MatchScore <= 0 || got[i].MatchScore > 1 {
+					t.Errorf("MockSymbolSearcher(%q)[%d] = %+v, want %s", tt.query, i, got[i], symbol)
+				}
+			}
+		})
+	}
+}
+
+func TestMockSymbolFetcher(t *testing.T) {
+	info, err := MockSymbolFetcher(context.Background(), "aapl")
+	if err != nil {
+		t.Fatalf("MockSymbolFetcher() error = %v", err)
+	}
+	if info.Symbol != "AAPL" || info.Exchange != "NASDAQ" || info.Currency != "USD" || info.MatchScore != 0 {
+		t.Errorf("MockSymbolFetcher() = %+v", info)
+	}
+
+	// Lookups accept any spelling of a symbol
+	info, err = MockSymbolFetcher(context.Background(), "brk-b")
+	if err != nil || info.Symbol != "BRK.B" {
+		t.Errorf("MockSymbolFetcher(brk-b) = %+v, %v; want BRK.B", info, err)
+	}
+
+	if _, err := MockSymbolFetcher(context.Background(), "TSLA"); err == nil {
+		t.Error("Expected error for unknown symbol")
+	}
+}
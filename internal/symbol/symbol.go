@@ -0,0 +1,224 @@
+// Package symbol parses ticker symbols as users write them and normalizes
+// them to one canonical form shared by every transport and provider.
+//
+// Canonical symbols are upper case. Share classes follow the base symbol
+// after a dot (BRK.B), as do exchange suffixes for listings outside the US
+// (SHOP.TO, 7203.T). Crypto pairs join base and quote currency with a
+// hyphen (BTC-USD).
+package symbol
+
+import (
+	"strings"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+)
+
+// AssetClass is the kind of instrument a symbol names
+type AssetClass string
+
+// Supported asset classes
+const (
+	AssetEquity AssetClass = "equity"
+	AssetCrypto AssetClass = "crypto"
+)
+
+const (
+	maxBaseLength  = 10
+	maxClassLength = 2
+)
+
+// Symbol is a parsed ticker symbol
+type Symbol struct {
+	// Base is the symbol without class, exchange or quote currency
+	Base string
+	// Class is the share class, e.g. "B" for BRK.B
+	Class string
+	// Exchange is the canonical exchange suffix, or "" for US listings
+	Exchange string
+	// Quote is the quote currency of a crypto pair
+	Quote string
+	Asset AssetClass
+}
+
+// exchange describes a listing venue outside the US
+type exchange struct {
+	// Suffix is the canonical suffix, as used by most market data vendors
+	Suffix string
+	// Code is the short name of the exchange
+	Code string
+	// Currency is the currency listings are priced in. London prices are
+	// in pence, GBX.
+	Currency string
+	// Aliases are other suffixes naming the exchange
+	Aliases []string
+}
+
+// exchanges lists the non-US venues recognised in exchange suffixes
+var exchanges = []exchange{
+	{Suffix: "TO", Code: "TSX", Currency: "CAD", Aliases: []string{"TRT"}},
+	{Suffix: "V", Code: "TSXV", Currency: "CAD", Aliases: []string{"TRV"}},
+	{Suffix: "L", Code: "LSE", Currency: "GBX", Aliases: []string{"LON"}},
+	{Suffix: "DE", Code: "XETRA", Currency: "EUR", Aliases: []string{"DEX"}},
+	{Suffix: "F", Code: "FWB", Currency: "EUR"},
+	{Suffix: "PA", Code: "EPA", Currency: "EUR"},
+	{Suffix: "AS", Code: "AMS", Currency: "EUR"},
+	{Suffix: "SW", Code: "SIX", Currency: "CHF"},
+	{Suffix: "T", Code: "TSE", Currency: "JPY"},
+	{Suffix: "HK", Code: "HKEX", Currency: "HKD"},
+	{Suffix: "SS", Code: "SSE", Currency: "CNY", Aliases: []string{"SHH"}},
+	{Suffix: "SZ", Code: "SZSE", Currency: "CNY", Aliases: []string{"SHZ"}},
+	{Suffix: "BO", Code: "BSE", Currency: "INR", Aliases: []string{"BSE"}},
+	{Suffix: "NS", Code: "NSE", Currency: "INR"},
+	{Suffix: "AX", Code: "ASX", Currency: "AUD"},
+}
+
+// exchangesBySuffix indexes exchanges by canonical suffix and alias
+var exchangesBySuffix = indexExchanges()
+
+func indexExchanges() map[string]exchange {
+	index := make(map[string]exchange)
+	for _, e := range exchanges {
+		index[e.Suffix] = e
+		for _, alias := range e.Aliases {
+			index[alias] = e
+		}
+	}
+	return index
+}
+
+// quoteCurrencies are the currencies crypto pairs are quoted in
+var quoteCurrencies = map[string]bool{
+	"USD": true, "USDT": true, "USDC": true, "EUR": true, "GBP": true,
+	"JPY": true, "CAD": true, "AUD": true, "BTC": true, "ETH": true,
+}
+
+// Parse parses a ticker symbol. Besides the canonical form it accepts
+// lower case, hyphenated share classes (BRK-B), slash-separated crypto
+// pairs (BTC/USD) and the exchange aliases used by some vendors (TSCO.LON).
+// A one-letter suffix that names an exchange, such as .T for Tokyo, is read
+// as the exchange; write such share classes with a hyphen.
+func Parse(ticker string) (Symbol, error) {
+	raw := strings.ToUpper(strings.TrimSpace(ticker))
+	if raw == "" {
+		return Symbol{}, apperror.InvalidArgument("ticker is required")
+	}
+
+	if base, quote, ok := strings.Cut(raw, "/"); ok {
+		return parsePair(ticker, base, quote)
+	}
+
+	var sym Symbol
+	rest := raw
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		if e, ok := exchangesBySuffix[rest[i+1:]]; ok {
+			sym.Exchange = e.Suffix
+			rest = rest[:i]
+		}
+	}
+
+	if i := strings.LastIndexAny(rest, ".-"); i >= 0 {
+		suffix := rest[i+1:]
+		switch {
+		case rest[i] == '-' && sym.Exchange == "" && quoteCurrencies[suffix]:
+			return parsePair(ticker, rest[:i], suffix)
+		case len(suffix) >= 1 && len(suffix) <= maxClassLength && isLetters(suffix):
+			sym.Class = suffix
+			rest = rest[:i]
+		case rest[i] == '.':
+			return Symbol{}, apperror.InvalidArgument("invalid ticker %q: unknown exchange suffix %q", ticker, suffix)
+		default:
+			return Symbol{}, apperror.InvalidArgument("invalid ticker %q: unknown share class or quote currency %q", ticker, suffix)
+		}
+	}
+
+	if !isAlphanumeric(rest, maxBaseLength) {
+		return Symbol{}, apperror.InvalidArgument("invalid ticker %q: symbol must be 1-%d letters or digits", ticker, maxBaseLength)
+	}
+	sym.Base = rest
+	sym.Asset = AssetEquity
+	return sym, nil
+}
+
+// parsePair parses the halves of a crypto pair
+func parsePair(ticker, base, quote string) (Symbol, error) {
+	if !isAlphanumeric(base, maxBaseLength) {
+		return Symbol{}, apperror.InvalidArgument("invalid ticker %q: crypto symbol must be 1-%d letters or digits", ticker, maxBaseLength)
+	}
+	if !quoteCurrencies[quote] {
+		return Symbol{}, apperror.InvalidArgument("invalid ticker %q: unsupported quote currency %q", ticker, quote)
+	}
+	return Symbol{Base: base, Quote: quote, Asset: AssetCrypto}, nil
+}
+
+// Normalize returns the canonical form of ticker
+func Normalize(ticker string) (string, error) {
+	sym, err := Parse(ticker)
+	if err != nil {
+		return "", err
+	}
+	return sym.String(), nil
+}
+
+// String returns the canonical form of the symbol
+func (s Symbol) String() string {
+	if s.Asset == AssetCrypto {
+		return s.Base + "-" + s.Quote
+	}
+
+	var b strings.Builder
+	b.WriteString(s.Base)
+	if s.Class != "" {
+		// A class spelled like an exchange suffix would be read back as one
+		if _, ok := exchangesBySuffix[s.Class]; ok {
+			b.WriteString("-")
+		} else {
+			b.WriteString(".")
+		}
+		b.WriteString(s.Class)
+	}
+	if s.Exchange != "" {
+		b.WriteString(".")
+		b.WriteString(s.Exchange)
+	}
+	return b.String()
+}
+
+// ExchangeCode returns the short name of the symbol's exchange, or "" for
+// US listings and crypto pairs
+func (s Symbol) ExchangeCode() string {
+	return exchangesBySuffix[s.Exchange].Code
+}
+
+// Currency returns the currency the symbol is priced in: the quote
+// currency of a crypto pair, the exchange's currency for listings outside
+// the US, and USD otherwise
+func (s Symbol) Currency() string {
+	if s.Asset == AssetCrypto {
+		return s.Quote
+	}
+	if e, ok := exchangesBySuffix[s.Exchange]; ok {
+		return e.Currency
+	}
+	return "USD"
+}
+
+func isLetters(value string) bool {
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(value string, maxLength int) bool {
+	if len(value) < 1 || len(value) > maxLength {
+		return false
+	}
+	for _, r := range value {
+		if !((r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
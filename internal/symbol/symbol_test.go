@@ -0,0 +1,84 @@
+package symbol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aliexe/ms-priceFetcher/internal/apperror"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      Symbol
+		canonical string
+	}{
+		{input: "AAPL", want: Symbol{Base: "AAPL", Asset: AssetEquity}, canonical: "AAPL"},
+		{input: " msft ", want: Symbol{Base: "MSFT", Asset: AssetEquity}, canonical: "MSFT"},
+		{input: "BRK.B", want: Symbol{Base: "BRK", Class: "B", Asset: AssetEquity}, canonical: "BRK.B"},
+		{input: "brk-b", want: Symbol{Base: "BRK", Class: "B", Asset: AssetEquity}, canonical: "BRK.B"},
+		{input: "BF-B", want: Symbol{Base: "BF", Class: "B", Asset: AssetEquity}, canonical: "BF.B"},
+		{input: "RDS-A", want: Symbol{Base: "RDS", Class: "A", Asset: AssetEquity}, canonical: "RDS.A"},
+		{input: "SHOP.TO", want: Symbol{Base: "SHOP", Exchange: "TO", Asset: AssetEquity}, canonical: "SHOP.TO"},
+		{input: "7203.T", want: Symbol{Base: "7203", Exchange: "T", Asset: AssetEquity}, canonical: "7203.T"},
+		{input: "TSCO.LON", want: Symbol{Base: "TSCO", Exchange: "L", Asset: AssetEquity}, canonical: "TSCO.L"},
+		{input: "CTC-A.TO", want: Symbol{Base: "CTC", Class: "A", Exchange: "TO", Asset: AssetEquity}, canonical: "CTC.A.TO"},
+		// A class spelled like an exchange suffix keeps its hyphen
+		{input: "ABC-T", want: Symbol{Base: "ABC", Class: "T", Asset: AssetEquity}, canonical: "ABC-T"},
+		{input: "BTC-USD", want: Symbol{Base: "BTC", Quote: "USD", Asset: AssetCrypto}, canonical: "BTC-USD"},
+		{input: "eth/btc", want: Symbol{Base: "ETH", Quote: "BTC", Asset: AssetCrypto}, canonical: "ETH-BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+			if got.String() != tt.canonical {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.input, got.String(), tt.canonical)
+			}
+
+			// The canonical form parses back to the same symbol
+			again, err := Parse(tt.canonical)
+			if err != nil || again != tt.want {
+				t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.canonical, again, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "TOOLONGSYMBOL", "AB$C", "BRK.XYZ", "BTC-XYZ", "BTC/", "/USD", "A.B.C", "BTC-USD.TO"} {
+		if _, err := Parse(input); !errors.Is(err, apperror.ErrInvalidArgument) {
+			t.Errorf("Parse(%q) error = %v, want invalid argument", input, err)
+		}
+	}
+}
+
+func TestSymbol_ExchangeCode(t *testing.T) {
+	for input, want := range map[string]string{"SHOP.TO": "TSX", "TSCO.LON": "LSE", "7203.T": "TSE", "AAPL": "", "BTC-USD": ""} {
+		sym, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+		if got := sym.ExchangeCode(); got != want {
+			t.Errorf("Parse(%q).ExchangeCode() = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSymbol_Currency(t *testing.T) {
+	for input, want := range map[string]string{"AAPL": "USD", "BRK.B": "USD", "SHOP.TO": "CAD", "TSCO.L": "GBX", "7203.T": "JPY", "BTC-USD": "USD", "ETH/BTC": "BTC"} {
+		sym, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+		if got := sym.Currency(); got != want {
+			t.Errorf("Parse(%q).Currency() = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package ticks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tick is one observed price
+type Tick struct {
+	Ticker string    `json:"ticker"`
+	Price  float64   `json:"price"`
+	Source string    `json:"source,omitempty"`
+	Time   time.Time `json:"time"`
+	// Stale is set for prices served from an expired cache entry
+	Stale bool `json:"stale,omitempty"`
+	// Seq is assigned by the store in the order ticks are recorded and
+	// keeps increasing across restarts. Ticks recorded before sequence
+	// numbers were kept have none.
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// Options controls how long ticks are kept and how they are thinned out
+type Options struct {
+	// Retention is how long ticks are kept. Zero keeps them forever.
+	Retention time.Duration
+	// DownsampleAfter is the age after which a segment is downsampled
+	DownsampleAfter time.Duration
+	// DownsampleInterval is the resolution of downsampled segments: the
+	// last tick per ticker in each interval is kept. Zero disables
+	// downsampling.
+	DownsampleInterval time.Duration
+}
+
+const (
+	// Each segment file holds the ticks observed within one hour
+	segmentDuration   = time.Hour
+	segmentTimeLayout = "20060102T15"
+	segmentPrefix     = "ticks-"
+	rawSuffix         = ".jsonl"
+	downsampledSuffix = ".ds.jsonl"
+)
+
+// Store is an append-only tick store. Ticks are written as JSON lines to
+// hourly segment files, which are later downsampled and finally deleted
+// according to Options.
+type Store struct {
+	dir  string
+	opts Options
+	// segmentsMutex is held exclusively by compaction, which replaces and
+	// deletes segments, and shared by everything else, so queries read
+	// segments while ticks are recorded
+	segmentsMutex sync.RWMutex
+	// writeMutex guards the segment being written, the last sequence
+	// number assigned and the watchers
+	writeMutex sync.Mutex
+	current    *os.File
+	start      time.Time
+	seq        uint64
+	watchers   map[*Watcher]struct{}
+}
+
+// segment is one segment file on disk
+type segment struct {
+	path        string
+	start       time.Time
+	downsampled bool
+}
+
+// NewStore creates a store under dir, creating the directory if needed
+func NewStore(dir string, opts Options) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create tick directory: %w", err)
+	}
+	s := &Store{dir: dir, opts: opts, watchers: make(map[*Watcher]struct{})}
+
+	// Sequence numbers start from the clock, so they keep increasing after
+	// a restart even once the segments recorded before it are gone, and
+	// from the newest recorded tick should the clock have gone back
+	s.seq = uint64(time.Now().UnixNano())
+	last, err := s.lastSeq()
+	if err != nil {
+		return nil, err
+	}
+	s.seq = max(s.seq, last)
+	return s, nil
+}
+
+// lastSeq returns the highest sequence number in the newest segment
+func (s *Store) lastSeq() (uint64, error) {
+	segments, err := s.segments()
+	if err != nil || len(segments) == 0 {
+		return 0, err
+	}
+	newest := segments[0]
+	for _, seg := range segments[1:] {
+		if seg.start.After(newest.start) {
+			newest = seg
+		}
+	}
+
+	ticks, err := readSegment(newest.path)
+	if err != nil {
+		return 0, err
+	}
+	var last uint64
+	for _, tick := range ticks {
+		last = max(last, tick.Seq)
+	}
+	return last, nil
+}
+
+// Record appends ticks to the segments covering their timestamps. Each
+// tick is assigned the next sequence number, set in ticks, and passed to
+// the watchers.
+func (s *Store) Record(ticks ...Tick) error {
+	s.segmentsMutex.RLock()
+	defer s.segmentsMutex.RUnlock()
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	for i := range ticks {
+		s.seq++
+		ticks[i].Seq = s.seq
+		tick := ticks[i]
+
+		line, err := json.Marshal(tick)
+		if err != nil {
+			return fmt.Errorf("failed to encode tick: %w", err)
+		}
+
+		file, err := s.segmentFor(tick.Time)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to record tick: %w", err)
+		}
+		s.notifyLocked(tick)
+	}
+	return nil
+}
+
+// segmentFor returns the open segment file for t, rolling over to a new
+// one when t falls outside the current segment
+func (s *Store) segmentFor(t time.Time) (*os.File, error) {
+	start := t.UTC().Truncate(segmentDuration)
+	if s.current != nil && start.Equal(s.start) {
+		return s.current, nil
+	}
+
+	if s.current != nil {
+		s.current.Close()
+		s.current = nil
+	}
+
+	path := filepath.Join(s.dir, segmentPrefix+start.Format(segmentTimeLayout)+rawSuffix)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tick segment: %w", err)
+	}
+	s.current = file
+	s.start = start
+	return file, nil
+}
+
+// Query returns the ticks recorded for ticker between from and to
+// inclusive, oldest first
+func (s *Store) Query(ticker string, from, to time.Time) ([]Tick, error) {
+	return s.QueryTickers([]string{ticker}, from, to)
+}
+
+// QueryTickers returns the ticks recorded for any of tickers between from
+// and to inclusive, oldest first. Each segment is read once, however many
+// tickers are asked for.
+func (s *Store) QueryTickers(tickers []string, from, to time.Time) ([]Tick, error) {
+	// Compaction cannot swap segments mid-scan; appends may land while a
+	// segment is read, and a line cut short is skipped
+	s.segmentsMutex.RLock()
+	defer s.segmentsMutex.RUnlock()
+
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(tickers))
+	for _, ticker := range tickers {
+		wanted[ticker] = true
+	}
+
+	result := make([]Tick, 0)
+	for _, seg := range segments {
+		if seg.start.After(to) || !seg.start.Add(segmentDuration).After(from) {
+			continue
+		}
+
+		ticks, err := readSegment(seg.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, tick := range ticks {
+			if wanted[tick.Ticker] && !tick.Time.Before(from) && !tick.Time.After(to) {
+				result = append(result, tick)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result, nil
+}
+
+// Compact deletes segments past retention and downsamples segments older
+// than DownsampleAfter
+func (s *Store) Compact(now time.Time) error {
+	// Holding segmentsMutex exclusively also keeps Record out, so the
+	// segment being written cannot change underneath
+	s.segmentsMutex.Lock()
+	defer s.segmentsMutex.Unlock()
+
+	segments, err := s.segments()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, seg := range segments {
+		// Leave the segment being written alone
+		if !seg.downsampled && s.current != nil && seg.start.Equal(s.start) {
+			continue
+		}
+		end := seg.start.Add(segmentDuration)
+
+		if s.opts.Retention > 0 && end.Before(now.Add(-s.opts.Retention)) {
+			if err := os.Remove(seg.path); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove tick segment: %w", err))
+			}
+			continue
+		}
+
+		if seg.downsampled || s.opts.DownsampleInterval <= 0 {
+			continue
+		}
+		if end.Before(now.Add(-s.opts.DownsampleAfter)) {
+			if err := s.downsample(seg); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// downsample replaces a raw segment with one keeping the last tick per
+// ticker in each DownsampleInterval. Ticks already downsampled for the same
+// hour are merged in.
+func (s *Store) downsample(seg segment) error {
+	ticks, err := readSegment(seg.path)
+	if err != nil {
+		return err
+	}
+
+	target := strings.TrimSuffix(seg.path, rawSuffix) + downsampledSuffix
+	previous, err := readSegment(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	ticks = append(previous, ticks...)
+
+	type bucket struct {
+		ticker string
+		start  time.Time
+	}
+	last := make(map[bucket]Tick)
+	for _, tick := range ticks {
+		key := bucket{tick.Ticker, tick.Time.Truncate(s.opts.DownsampleInterval)}
+		if kept, ok := last[key]; !ok || !tick.Time.Before(kept.Time) {
+			last[key] = tick
+		}
+	}
+
+	kept := make([]Tick, 0, len(last))
+	for _, tick := range last {
+		kept = append(kept, tick)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Time.Before(kept[j].Time) })
+
+	if err := writeSegment(s.dir, target, kept); err != nil {
+		return err
+	}
+	if err := os.Remove(seg.path); err != nil {
+		return fmt.Errorf("failed to remove tick segment: %w", err)
+	}
+	return nil
+}
+
+// StartCompactor runs Compact every interval until ctx is cancelled
+func (s *Store) StartCompactor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.Compact(now); err != nil {
+				logrus.WithError(err).Warn("Tick compaction failed")
+			}
+		}
+	}
+}
+
+// Close closes the segment being written
+func (s *Store) Close() error {
+	s.segmentsMutex.RLock()
+	defer s.segmentsMutex.RUnlock()
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if s.current == nil {
+		return nil
+	}
+	err := s.current.Close()
+	s.current = nil
+	s.start = time.Time{}
+	return err
+}
+
+// segments lists the segment files in the store directory
+func (s *Store) segments() ([]segment, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tick segments: %w", err)
+	}
+
+	var segments []segment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) {
+			continue
+		}
+
+		seg := segment{path: filepath.Join(s.dir, name)}
+		stamp := strings.TrimPrefix(name, segmentPrefix)
+		switch {
+		case strings.HasSuffix(stamp, downsampledSuffix):
+			stamp = strings.TrimSuffix(stamp, downsampledSuffix)
+			seg.downsampled = true
+		case strings.HasSuffix(stamp, rawSuffix):
+			stamp = strings.TrimSuffix(stamp, rawSuffix)
+		default:
+			continue
+		}
+
+		start, err := time.Parse(segmentTimeLayout, stamp)
+		if err != nil {
+			continue
+		}
+		seg.start = start
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// readSegment decodes a segment file. A line cut short by a crash or a
+// concurrent append is skipped.
+func readSegment(path string) ([]Tick, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tick segment: %w", err)
+	}
+	defer file.Close()
+
+	var ticks []Tick
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var tick Tick
+		if err := json.Unmarshal(scanner.Bytes(), &tick); err != nil {
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tick segment: %w", err)
+	}
+	return ticks, nil
+}
+
+// writeSegment atomically replaces path with ticks
+func writeSegment(dir, path string, ticks []Tick) error {
+	tmp, err := os.CreateTemp(dir, ".segment-*")
+	if err != nil {
+		return fmt.Errorf("failed to write tick segment: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, tick := range ticks {
+		if err := encoder.Encode(tick); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write tick segment: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write tick segment: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write tick segment: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write tick segment: %w", err)
+	}
+	return nil
+}
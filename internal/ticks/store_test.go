@@ -0,0 +1,216 @@
+package ticks
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_RecordAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	base := time.Date(2024, 1, 2, 14, 50, 0, 0, time.UTC)
+	err = store.Record(
+		Tick{Ticker: "AAPL", Price: 150, Source: "mock", Time: base},
+		Tick{Ticker: "MSFT", Price: 300, Source: "mock", Time: base.Add(time.Minute)},
+		// Crosses into the next hourly segment
+		Tick{Ticker: "AAPL", Price: 151, Source: "mock", Time: base.Add(20 * time.Minute)},
+	)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	store.Close()
+
+	// Reopening the store keeps what was recorded
+	store, _ = NewStore(dir, Options{})
+	store.Record(Tick{Ticker: "AAPL", Price: 152, Time: base.Add(2 * time.Hour)})
+	defer store.Close()
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		to     time.Time
+		prices []float64
+	}{
+		{name: "Everything", from: base.Add(-time.Hour), to: base.Add(3 * time.Hour), prices: []float64{150, 151, 152}},
+		{name: "Across segments", from: base, to: base.Add(30 * time.Minute), prices: []float64{150, 151}},
+		{name: "Inclusive bounds", from: base.Add(20 * time.Minute), to: base.Add(2 * time.Hour), prices: []float64{151, 152}},
+		{name: "Nothing recorded", from: base.Add(4 * time.Hour), to: base.Add(5 * time.Hour), prices: []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query("AAPL", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(got) != len(tt.prices) {
+				t.Fatalf("Query() returned %d ticks, want %d", len(got), len(tt.prices))
+			}
+			for i, price := range tt.prices {
+				if got[i].Price != price {
+					t.Errorf("Query()[%d].Price = %v, want %v", i, got[i].Price, price)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Sequence(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	watcher := store.Watch(2)
+	now := time.Now()
+	recorded := []Tick{
+		{Ticker: "AAPL", Price: 150, Time: now},
+		// Observed earlier, recorded later
+		{Ticker: "MSFT", Price: 300, Time: now.Add(-time.Minute)},
+	}
+	if err := store.Record(recorded...); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if recorded[0].Seq == 0 || recorded[1].Seq <= recorded[0].Seq {
+		t.Errorf("sequence numbers = %d, %d, want increasing", recorded[0].Seq, recorded[1].Seq)
+	}
+
+	// Watchers get the ticks in the order they were recorded
+	for _, want := range recorded {
+		if got := <-watcher.Ticks(); got != want {
+			t.Errorf("watched tick = %+v, want %+v", got, want)
+		}
+	}
+
+	// A watcher that falls behind is closed
+	store.Record(recorded[0], recorded[0], recorded[0])
+	count := 0
+	for range watcher.Ticks() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("watcher got %d ticks before being closed, want its buffer of 2", count)
+	}
+	watcher.Close()
+
+	got, err := store.QueryTickers([]string{"AAPL", "MSFT"}, now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("QueryTickers() error = %v", err)
+	}
+	if len(got) != 5 || got[0].Ticker != "MSFT" || got[0].Seq != recorded[1].Seq {
+		t.Errorf("QueryTickers() = %+v, want 5 ticks with their sequence numbers", got)
+	}
+	var last uint64
+	for _, tick := range got {
+		last = max(last, tick.Seq)
+	}
+	store.Close()
+
+	// Numbering carries on after a restart
+	store, _ = NewStore(dir, Options{})
+	defer store.Close()
+	next := []Tick{{Ticker: "AAPL", Price: 151, Time: now}}
+	store.Record(next...)
+	if next[0].Seq <= last {
+		t.Errorf("sequence after restart = %d, want above %d", next[0].Seq, last)
+	}
+}
+
+func TestStore_RecordDuringQuery(t *testing.T) {
+	store, err := NewStore(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	defer store.Close()
+
+	// A query in progress holds the segments for reading
+	store.segmentsMutex.RLock()
+	recorded := make(chan error, 1)
+	go func() { recorded <- store.Record(Tick{Ticker: "AAPL", Price: 150, Time: time.Now()}) }()
+
+	select {
+	case err := <-recorded:
+		if err != nil {
+			t.Errorf("Record() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Record() blocked behind a query")
+	}
+	store.segmentsMutex.RUnlock()
+}
+
+func TestStore_CompactDownsamples(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir, Options{
+		DownsampleAfter:    time.Hour,
+		DownsampleInterval: time.Minute,
+	})
+	defer store.Close()
+
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		// Two ticks per minute over three minutes
+		store.Record(Tick{Ticker: "AAPL", Price: float64(100 + i), Time: base.Add(time.Duration(i) * 30 * time.Second)})
+	}
+	// A tick in the current hour keeps its segment open
+	now := base.Add(3 * time.Hour)
+	store.Record(Tick{Ticker: "AAPL", Price: 200, Time: now})
+
+	if err := store.Compact(now); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	got, _ := store.Query("AAPL", base, base.Add(time.Hour))
+	want := []float64{101, 103, 105}
+	if len(got) != len(want) {
+		t.Fatalf("Query() after downsampling returned %d ticks, want %d", len(got), len(want))
+	}
+	for i, price := range want {
+		if got[i].Price != price {
+			t.Errorf("Query()[%d].Price = %v, want last tick of the minute %v", i, got[i].Price, price)
+		}
+	}
+
+	if recent, _ := store.Query("AAPL", now, now); len(recent) != 1 {
+		t.Errorf("Expected the open segment to be left alone, got %d ticks", len(recent))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	downsampled := 0
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), downsampledSuffix) {
+			downsampled++
+		}
+	}
+	if downsampled != 1 {
+		t.Errorf("Downsampled segments = %d, want 1", downsampled)
+	}
+}
+
+func TestStore_CompactRetention(t *testing.T) {
+	store, _ := NewStore(t.TempDir(), Options{Retention: 24 * time.Hour})
+	defer store.Close()
+
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	store.Record(
+		Tick{Ticker: "AAPL", Price: 100, Time: now.Add(-48 * time.Hour)},
+		Tick{Ticker: "AAPL", Price: 110, Time: now.Add(-2 * time.Hour)},
+		Tick{Ticker: "AAPL", Price: 120, Time: now},
+	)
+
+	if err := store.Compact(now); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	got, _ := store.Query("AAPL", now.Add(-72*time.Hour), now)
+	if len(got) != 2 || got[0].Price != 110 || got[1].Price != 120 {
+		t.Errorf("Query() after retention = %+v, want ticks 110 and 120", got)
+	}
+}
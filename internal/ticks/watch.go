@@ -0,0 +1,56 @@
+package ticks
+
+// Watcher receives the ticks recorded after it was created, in the order
+// they were recorded. A watcher that falls a full buffer behind is closed.
+type Watcher struct {
+	store *Store
+	ticks chan Tick
+
+	// Guarded by store.writeMutex
+	closed bool
+}
+
+// Watch creates a watcher buffering up to buffer ticks. It must be closed
+// when no longer read.
+func (s *Store) Watch(buffer int) *Watcher {
+	w := &Watcher{store: s, ticks: make(chan Tick, buffer)}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	s.watchers[w] = struct{}{}
+	return w
+}
+
+// Ticks returns the channel ticks are delivered on. It is closed when the
+// watcher is closed or falls behind.
+func (w *Watcher) Ticks() <-chan Tick {
+	return w.ticks
+}
+
+// Close stops delivery. Closing twice is a no-op.
+func (w *Watcher) Close() {
+	w.store.writeMutex.Lock()
+	defer w.store.writeMutex.Unlock()
+	w.closeLocked()
+}
+
+func (w *Watcher) closeLocked() {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	delete(w.store.watchers, w)
+	close(w.ticks)
+}
+
+// notifyLocked passes a recorded tick to every watcher without blocking,
+// closing watchers whose buffer is full
+func (s *Store) notifyLocked(tick Tick) {
+	for w := range s.watchers {
+		select {
+		case w.ticks <- tick:
+		default:
+			w.closeLocked()
+		}
+	}
+}
@@ -1,26 +1,110 @@
 package types
 
 type PriceResponse struct {
-	Ticker string  `json:"ticker"`
-	Price  float64 `json:"price"`
+	Ticker   string  `json:"ticker"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+	Source   string  `json:"source,omitempty"`
+	Stale    bool    `json:"stale,omitempty"`
+	AsOf     string  `json:"as_of,omitempty"`
 }
 
+// BatchPriceResponse holds prices by ticker. Unless converted to one
+// currency, tickers may be priced in different ones, given in Currencies.
 type BatchPriceResponse struct {
-	Prices map[string]float64 `json:"prices"`
-	Errors []string           `json:"errors,omitempty"`
+	Prices     map[string]float64 `json:"prices"`
+	Currencies map[string]string  `json:"currencies"`
+	Sources    map[string]string  `json:"sources,omitempty"`
+	Stale      []string           `json:"stale,omitempty"`
+	AsOf       map[string]string  `json:"as_of,omitempty"`
+	Errors     []string           `json:"errors,omitempty"`
+}
+
+// Quote is the latest trading session of a ticker. LatestTradingDay is
+// YYYY-MM-DD and ChangePercent is in percent, so 1.5 means a 1.5% gain.
+type Quote struct {
+	Ticker           string  `json:"ticker"`
+	Open             float64 `json:"open"`
+	High             float64 `json:"high"`
+	Low              float64 `json:"low"`
+	Price            float64 `json:"price"`
+	Volume           int64   `json:"volume"`
+	LatestTradingDay string  `json:"latest_trading_day"`
+	PreviousClose    float64 `json:"previous_close"`
+	Change           float64 `json:"change"`
+	ChangePercent    float64 `json:"change_percent"`
+}
+
+type QuoteResponse struct {
+	Quote
+	Currency string `json:"currency"`
+	Source   string `json:"source,omitempty"`
+	Stale    bool   `json:"stale,omitempty"`
+	AsOf     string `json:"as_of,omitempty"`
+}
+
+// SymbolInfo describes a listed symbol. MatchScore ranks search results
+// from 0 to 1 and is omitted from metadata lookups.
+type SymbolInfo struct {
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Exchange   string  `json:"exchange,omitempty"`
+	Type       string  `json:"type"`
+	Region     string  `json:"region"`
+	Currency   string  `json:"currency"`
+	MatchScore float64 `json:"match_score,omitempty"`
 }
 
+type SymbolSearchResponse struct {
+	Query   string       `json:"query"`
+	Source  string       `json:"source,omitempty"`
+	Results []SymbolInfo `json:"results"`
+}
+
+type SymbolResponse struct {
+	SymbolInfo
+	Source string `json:"source,omitempty"`
+}
+
+// HistoricalPricePoint is one bar. Date is YYYY-MM-DD for daily bars and an
+// RFC3339 time for intraday bars. Prices are as traded; daily bars also carry
+// the close adjusted for later splits and dividends, and the corporate
+// actions taking effect that day.
 type HistoricalPricePoint struct {
-	Date  string  `json:"date"`
-	Open  float64 `json:"open"`
-	High  float64 `json:"high"`
-	Low   float64 `json:"low"`
-	Close float64 `json:"close"`
+	Date             string  `json:"date"`
+	Open             float64 `json:"open"`
+	High             float64 `json:"high"`
+	Low              float64 `json:"low"`
+	Close            float64 `json:"close"`
+	Volume           int64   `json:"volume"`
+	AdjustedClose    float64 `json:"adjusted_close,omitempty"`
+	DividendAmount   float64 `json:"dividend_amount,omitempty"`
+	SplitCoefficient float64 `json:"split_coefficient,omitempty"`
 }
 
 type HistoricalPriceResponse struct {
-	Ticker string                 `json:"ticker"`
-	Data   []HistoricalPricePoint `json:"data"`
+	Ticker   string                 `json:"ticker"`
+	Interval string                 `json:"interval,omitempty"`
+	Period   string                 `json:"period,omitempty"`
+	Adjusted bool                   `json:"adjusted,omitempty"`
+	Currency string                 `json:"currency"`
+	Source   string                 `json:"source,omitempty"`
+	Stale    bool                   `json:"stale,omitempty"`
+	AsOf     string                 `json:"as_of,omitempty"`
+	Data     []HistoricalPricePoint `json:"data"`
+}
+
+type TickPoint struct {
+	Time   string  `json:"time"`
+	Price  float64 `json:"price"`
+	Source string  `json:"source,omitempty"`
+}
+
+type TickResponse struct {
+	Ticker string      `json:"ticker"`
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Ticks  []TickPoint `json:"ticks"`
 }
 
 type CreateAlertRequest struct {
@@ -44,3 +128,50 @@ type Alert struct {
 	CreatedAt   string     `json:"created_at"`
 	TriggeredAt *string    `json:"triggered_at,omitempty"`
 }
+
+// SubscribeRequest changes the subscriptions of a price stream. Exactly one
+// of Subscribe and Unsubscribe is set; RequestID is echoed in the ack.
+type SubscribeRequest struct {
+	RequestID   string      `json:"request_id,omitempty"`
+	Subscribe   *TickerList `json:"subscribe,omitempty"`
+	Unsubscribe *TickerList `json:"unsubscribe,omitempty"`
+}
+
+type TickerList struct {
+	Tickers []string `json:"tickers"`
+}
+
+// SubscribeResponse is one message of a price stream. Exactly one field is
+// set.
+type SubscribeResponse struct {
+	Ack   *SubscriptionAck `json:"ack,omitempty"`
+	Price *StreamPrice     `json:"price,omitempty"`
+	Error *TickerError     `json:"error,omitempty"`
+}
+
+// SubscriptionAck lists every ticker subscribed to after a change, and the
+// tickers of the request that were not subscribed to
+type SubscriptionAck struct {
+	RequestID string        `json:"request_id,omitempty"`
+	Tickers   []string      `json:"tickers"`
+	Errors    []TickerError `json:"errors,omitempty"`
+}
+
+// StreamPrice is a price pushed to a stream. Timestamp is when it was polled.
+type StreamPrice struct {
+	Ticker    string  `json:"ticker"`
+	Price     float64 `json:"price"`
+	Currency  string  `json:"currency"`
+	Timestamp string  `json:"timestamp"`
+	Source    string  `json:"source,omitempty"`
+	Stale     bool    `json:"stale,omitempty"`
+	AsOf      string  `json:"as_of,omitempty"`
+}
+
+// TickerError is a ticker that could not be subscribed to or priced. Code
+// is the gRPC status code name, such as NotFound.
+type TickerError struct {
+	Ticker  string `json:"ticker,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -66,9 +66,14 @@ func (x *FetchPriceRequest) GetTicker() string {
 }
 
 type FetchPriceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
-	Price         float32                `protobuf:"fixed32,2,opt,name=price,proto3" json:"price,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Price  float32                `protobuf:"fixed32,2,opt,name=price,proto3" json:"price,omitempty"`
+	Source string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Stale  bool                   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf   string                 `protobuf:"bytes,5,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	// ISO 4217 code, or GBX for London listings priced in pence
+	Currency      string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,12 +122,56 @@ func (x *FetchPriceResponse) GetPrice() float32 {
 	return 0
 }
 
+func (x *FetchPriceResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FetchPriceResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *FetchPriceResponse) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *FetchPriceResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type StreamPricesRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Tickers         []string               `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
-	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Tickers []string               `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	// Seconds between prices, 5 by default. The server bounds the intervals
+	// it accepts.
+	IntervalSeconds int32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	// every_tick (default), on_change or min_delta
+	Mode string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	// For min_delta: the move from the last price sent that is sent, as an
+	// amount in the ticker's currency or a percentage. Set exactly one.
+	MinDeltaAmount  float64 `protobuf:"fixed64,4,opt,name=min_delta_amount,json=minDeltaAmount,proto3" json:"min_delta_amount,omitempty"`
+	MinDeltaPercent float64 `protobuf:"fixed64,5,opt,name=min_delta_percent,json=minDeltaPercent,proto3" json:"min_delta_percent,omitempty"`
+	// How long the stream may be idle before a keepalive is sent. Defaults
+	// to 30 for on_change and min_delta; every_tick sends none unless set.
+	KeepaliveSeconds int32 `protobuf:"varint,6,opt,name=keepalive_seconds,json=keepaliveSeconds,proto3" json:"keepalive_seconds,omitempty"`
+	// Poll intervals for some of the tickers, overriding interval_seconds
+	TickerIntervalSeconds map[string]int32 `protobuf:"bytes,7,rep,name=ticker_interval_seconds,json=tickerIntervalSeconds,proto3" json:"ticker_interval_seconds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Sends each ticker's current price right away instead of after its
+	// first interval
+	SnapshotFirst bool `protobuf:"varint,8,opt,name=snapshot_first,json=snapshotFirst,proto3" json:"snapshot_first,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StreamPricesRequest) Reset() {
@@ -169,11 +218,64 @@ func (x *StreamPricesRequest) GetIntervalSeconds() int32 {
 	return 0
 }
 
+func (x *StreamPricesRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *StreamPricesRequest) GetMinDeltaAmount() float64 {
+	if x != nil {
+		return x.MinDeltaAmount
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetMinDeltaPercent() float64 {
+	if x != nil {
+		return x.MinDeltaPercent
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetKeepaliveSeconds() int32 {
+	if x != nil {
+		return x.KeepaliveSeconds
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetTickerIntervalSeconds() map[string]int32 {
+	if x != nil {
+		return x.TickerIntervalSeconds
+	}
+	return nil
+}
+
+func (x *StreamPricesRequest) GetSnapshotFirst() bool {
+	if x != nil {
+		return x.SnapshotFirst
+	}
+	return false
+}
+
 type StreamPricesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
-	Price         float32                `protobuf:"fixed32,2,opt,name=price,proto3" json:"price,omitempty"`
-	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Ticker    string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Price     float32                `protobuf:"fixed32,2,opt,name=price,proto3" json:"price,omitempty"`
+	Timestamp string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Stale     bool                   `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf      string                 `protobuf:"bytes,6,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	Currency  string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	// The last price sent for the ticker and the move since, unset on its
+	// first price
+	PreviousPrice float32 `protobuf:"fixed32,8,opt,name=previous_price,json=previousPrice,proto3" json:"previous_price,omitempty"`
+	Change        float32 `protobuf:"fixed32,9,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64 `protobuf:"fixed64,10,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	// Keepalives carry only the timestamp
+	Keepalive     bool `protobuf:"varint,11,opt,name=keepalive,proto3" json:"keepalive,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -229,27 +331,318 @@ func (x *StreamPricesResponse) GetTimestamp() string {
 	return ""
 }
 
+func (x *StreamPricesResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *StreamPricesResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *StreamPricesResponse) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *StreamPricesResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *StreamPricesResponse) GetPreviousPrice() float32 {
+	if x != nil {
+		return x.PreviousPrice
+	}
+	return 0
+}
+
+func (x *StreamPricesResponse) GetChange() float32 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *StreamPricesResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *StreamPricesResponse) GetKeepalive() bool {
+	if x != nil {
+		return x.Keepalive
+	}
+	return false
+}
+
+type FetchQuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchQuoteRequest) Reset() {
+	*x = FetchQuoteRequest{}
+	mi := &file_proto_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchQuoteRequest) ProtoMessage() {}
+
+func (x *FetchQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchQuoteRequest.ProtoReflect.Descriptor instead.
+func (*FetchQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FetchQuoteRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type FetchQuoteResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Ticker           string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Open             float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
+	High             float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low              float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	Price            float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Volume           int64                  `protobuf:"varint,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	LatestTradingDay string                 `protobuf:"bytes,7,opt,name=latest_trading_day,json=latestTradingDay,proto3" json:"latest_trading_day,omitempty"`
+	PreviousClose    float64                `protobuf:"fixed64,8,opt,name=previous_close,json=previousClose,proto3" json:"previous_close,omitempty"`
+	Change           float64                `protobuf:"fixed64,9,opt,name=change,proto3" json:"change,omitempty"`
+	// Percent change on the previous close, so 1.5 means a 1.5% gain
+	ChangePercent float64 `protobuf:"fixed64,10,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	Source        string  `protobuf:"bytes,11,opt,name=source,proto3" json:"source,omitempty"`
+	Stale         bool    `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf          string  `protobuf:"bytes,13,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	Currency      string  `protobuf:"bytes,14,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchQuoteResponse) Reset() {
+	*x = FetchQuoteResponse{}
+	mi := &file_proto_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchQuoteResponse) ProtoMessage() {}
+
+func (x *FetchQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchQuoteResponse.ProtoReflect.Descriptor instead.
+func (*FetchQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FetchQuoteResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetLatestTradingDay() string {
+	if x != nil {
+		return x.LatestTradingDay
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetPreviousClose() float64 {
+	if x != nil {
+		return x.PreviousClose
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *FetchQuoteResponse) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 var File_proto_service_proto protoreflect.FileDescriptor
 
 const file_proto_service_proto_rawDesc = "" +
 	"\n" +
 	"\x13proto/service.proto\"+\n" +
 	"\x11FetchPriceRequest\x12\x16\n" +
-	"\x06ticker\x18\x01 \x01(\tR\x06ticker\"B\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\"\xa1\x01\n" +
 	"\x12FetchPriceResponse\x12\x16\n" +
 	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x14\n" +
-	"\x05price\x18\x02 \x01(\x02R\x05price\"Z\n" +
+	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12\x13\n" +
+	"\x05as_of\x18\x05 \x01(\tR\x04asOf\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\"\xcb\x03\n" +
 	"\x13StreamPricesRequest\x12\x18\n" +
 	"\atickers\x18\x01 \x03(\tR\atickers\x12)\n" +
-	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"b\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12(\n" +
+	"\x10min_delta_amount\x18\x04 \x01(\x01R\x0eminDeltaAmount\x12*\n" +
+	"\x11min_delta_percent\x18\x05 \x01(\x01R\x0fminDeltaPercent\x12+\n" +
+	"\x11keepalive_seconds\x18\x06 \x01(\x05R\x10keepaliveSeconds\x12g\n" +
+	"\x17ticker_interval_seconds\x18\a \x03(\v2/.StreamPricesRequest.TickerIntervalSecondsEntryR\x15tickerIntervalSeconds\x12%\n" +
+	"\x0esnapshot_first\x18\b \x01(\bR\rsnapshotFirst\x1aH\n" +
+	"\x1aTickerIntervalSecondsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xc5\x02\n" +
 	"\x14StreamPricesResponse\x12\x16\n" +
 	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp2\x84\x01\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x05 \x01(\bR\x05stale\x12\x13\n" +
+	"\x05as_of\x18\x06 \x01(\tR\x04asOf\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12%\n" +
+	"\x0eprevious_price\x18\b \x01(\x02R\rpreviousPrice\x12\x16\n" +
+	"\x06change\x18\t \x01(\x02R\x06change\x12%\n" +
+	"\x0echange_percent\x18\n" +
+	" \x01(\x01R\rchangePercent\x12\x1c\n" +
+	"\tkeepalive\x18\v \x01(\bR\tkeepalive\"+\n" +
+	"\x11FetchQuoteRequest\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\"\x87\x03\n" +
+	"\x12FetchQuoteResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x03R\x06volume\x12,\n" +
+	"\x12latest_trading_day\x18\a \x01(\tR\x10latestTradingDay\x12%\n" +
+	"\x0eprevious_close\x18\b \x01(\x01R\rpreviousClose\x12\x16\n" +
+	"\x06change\x18\t \x01(\x01R\x06change\x12%\n" +
+	"\x0echange_percent\x18\n" +
+	" \x01(\x01R\rchangePercent\x12\x16\n" +
+	"\x06source\x18\v \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\x12\x13\n" +
+	"\x05as_of\x18\r \x01(\tR\x04asOf\x12\x1a\n" +
+	"\bcurrency\x18\x0e \x01(\tR\bcurrency2\xbb\x01\n" +
 	"\fPriceFetcher\x125\n" +
 	"\n" +
 	"FetchPrice\x12\x12.FetchPriceRequest\x1a\x13.FetchPriceResponse\x12=\n" +
-	"\fStreamPrices\x12\x14.StreamPricesRequest\x1a\x15.StreamPricesResponse0\x01B8Z6github.com/aliaksandrp/microservice-priceFetcher/protob\x06proto3"
+	"\fStreamPrices\x12\x14.StreamPricesRequest\x1a\x15.StreamPricesResponse0\x01\x125\n" +
+	"\n" +
+	"FetchQuote\x12\x12.FetchQuoteRequest\x1a\x13.FetchQuoteResponseB8Z6github.com/aliaksandrp/microservice-priceFetcher/protob\x06proto3"
 
 var (
 	file_proto_service_proto_rawDescOnce sync.Once
@@ -263,23 +656,29 @@ func file_proto_service_proto_rawDescGZIP() []byte {
 	return file_proto_service_proto_rawDescData
 }
 
-var file_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_service_proto_goTypes = []any{
 	(*FetchPriceRequest)(nil),    // 0: FetchPriceRequest
 	(*FetchPriceResponse)(nil),   // 1: FetchPriceResponse
 	(*StreamPricesRequest)(nil),  // 2: StreamPricesRequest
 	(*StreamPricesResponse)(nil), // 3: StreamPricesResponse
+	(*FetchQuoteRequest)(nil),    // 4: FetchQuoteRequest
+	(*FetchQuoteResponse)(nil),   // 5: FetchQuoteResponse
+	nil,                          // 6: StreamPricesRequest.TickerIntervalSecondsEntry
 }
 var file_proto_service_proto_depIdxs = []int32{
-	0, // 0: PriceFetcher.FetchPrice:input_type -> FetchPriceRequest
-	2, // 1: PriceFetcher.StreamPrices:input_type -> StreamPricesRequest
-	1, // 2: PriceFetcher.FetchPrice:output_type -> FetchPriceResponse
-	3, // 3: PriceFetcher.StreamPrices:output_type -> StreamPricesResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6, // 0: StreamPricesRequest.ticker_interval_seconds:type_name -> StreamPricesRequest.TickerIntervalSecondsEntry
+	0, // 1: PriceFetcher.FetchPrice:input_type -> FetchPriceRequest
+	2, // 2: PriceFetcher.StreamPrices:input_type -> StreamPricesRequest
+	4, // 3: PriceFetcher.FetchQuote:input_type -> FetchQuoteRequest
+	1, // 4: PriceFetcher.FetchPrice:output_type -> FetchPriceResponse
+	3, // 5: PriceFetcher.StreamPrices:output_type -> StreamPricesResponse
+	5, // 6: PriceFetcher.FetchQuote:output_type -> FetchQuoteResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_proto_service_proto_init() }
@@ -293,7 +692,7 @@ func file_proto_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_service_proto_rawDesc), len(file_proto_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	PriceFetcher_FetchPrice_FullMethodName   = "/PriceFetcher/FetchPrice"
 	PriceFetcher_StreamPrices_FullMethodName = "/PriceFetcher/StreamPrices"
+	PriceFetcher_FetchQuote_FullMethodName   = "/PriceFetcher/FetchQuote"
 )
 
 // PriceFetcherClient is the client API for PriceFetcher service.
@@ -29,6 +30,7 @@ const (
 type PriceFetcherClient interface {
 	FetchPrice(ctx context.Context, in *FetchPriceRequest, opts ...grpc.CallOption) (*FetchPriceResponse, error)
 	StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamPricesResponse], error)
+	FetchQuote(ctx context.Context, in *FetchQuoteRequest, opts ...grpc.CallOption) (*FetchQuoteResponse, error)
 }
 
 type priceFetcherClient struct {
@@ -68,12 +70,23 @@ func (c *priceFetcherClient) StreamPrices(ctx context.Context, in *StreamPricesR
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PriceFetcher_StreamPricesClient = grpc.ServerStreamingClient[StreamPricesResponse]
 
+func (c *priceFetcherClient) FetchQuote(ctx context.Context, in *FetchQuoteRequest, opts ...grpc.CallOption) (*FetchQuoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchQuoteResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_FetchQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PriceFetcherServer is the server API for PriceFetcher service.
 // All implementations must embed UnimplementedPriceFetcherServer
 // for forward compatibility.
 type PriceFetcherServer interface {
 	FetchPrice(context.Context, *FetchPriceRequest) (*FetchPriceResponse, error)
 	StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[StreamPricesResponse]) error
+	FetchQuote(context.Context, *FetchQuoteRequest) (*FetchQuoteResponse, error)
 	mustEmbedUnimplementedPriceFetcherServer()
 }
 
@@ -90,6 +103,9 @@ func (UnimplementedPriceFetcherServer) FetchPrice(context.Context, *FetchPriceRe
 func (UnimplementedPriceFetcherServer) StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[StreamPricesResponse]) error {
 	return status.Error(codes.Unimplemented, "method StreamPrices not implemented")
 }
+func (UnimplementedPriceFetcherServer) FetchQuote(context.Context, *FetchQuoteRequest) (*FetchQuoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FetchQuote not implemented")
+}
 func (UnimplementedPriceFetcherServer) mustEmbedUnimplementedPriceFetcherServer() {}
 func (UnimplementedPriceFetcherServer) testEmbeddedByValue()                      {}
 
@@ -140,6 +156,24 @@ func _PriceFetcher_StreamPrices_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PriceFetcher_StreamPricesServer = grpc.ServerStreamingServer[StreamPricesResponse]
 
+func _PriceFetcher_FetchQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).FetchQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_FetchQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).FetchQuote(ctx, req.(*FetchQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PriceFetcher_ServiceDesc is the grpc.ServiceDesc for PriceFetcher service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +185,10 @@ var PriceFetcher_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchPrice",
 			Handler:    _PriceFetcher_FetchPrice_Handler,
 		},
+		{
+			MethodName: "FetchQuote",
+			Handler:    _PriceFetcher_FetchQuote_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,2356 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.1
+// source: proto/v2/service.proto
+
+// Version 2 of the price fetcher API. Money is exact, as units and nanos of
+// a currency, and times are google.protobuf.Timestamp. Version 1 is still
+// served alongside it.
+
+package protov2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamMode int32
+
+const (
+	// Sends every price, like STREAM_MODE_EVERY_TICK
+	StreamMode_STREAM_MODE_UNSPECIFIED StreamMode = 0
+	StreamMode_STREAM_MODE_EVERY_TICK  StreamMode = 1
+	// Sends a price only when it differs from the last one sent
+	StreamMode_STREAM_MODE_ON_CHANGE StreamMode = 2
+	// Sends a price only when it moved at least the minimum delta from the
+	// last one sent
+	StreamMode_STREAM_MODE_MIN_DELTA StreamMode = 3
+)
+
+// Enum value maps for StreamMode.
+var (
+	StreamMode_name = map[int32]string{
+		0: "STREAM_MODE_UNSPECIFIED",
+		1: "STREAM_MODE_EVERY_TICK",
+		2: "STREAM_MODE_ON_CHANGE",
+		3: "STREAM_MODE_MIN_DELTA",
+	}
+	StreamMode_value = map[string]int32{
+		"STREAM_MODE_UNSPECIFIED": 0,
+		"STREAM_MODE_EVERY_TICK":  1,
+		"STREAM_MODE_ON_CHANGE":   2,
+		"STREAM_MODE_MIN_DELTA":   3,
+	}
+)
+
+func (x StreamMode) Enum() *StreamMode {
+	p := new(StreamMode)
+	*p = x
+	return p
+}
+
+func (x StreamMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_service_proto_enumTypes[0].Descriptor()
+}
+
+func (StreamMode) Type() protoreflect.EnumType {
+	return &file_proto_v2_service_proto_enumTypes[0]
+}
+
+func (x StreamMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamMode.Descriptor instead.
+func (StreamMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{0}
+}
+
+type AlertCondition int32
+
+const (
+	AlertCondition_ALERT_CONDITION_UNSPECIFIED AlertCondition = 0
+	AlertCondition_ALERT_CONDITION_ABOVE       AlertCondition = 1
+	AlertCondition_ALERT_CONDITION_BELOW       AlertCondition = 2
+)
+
+// Enum value maps for AlertCondition.
+var (
+	AlertCondition_name = map[int32]string{
+		0: "ALERT_CONDITION_UNSPECIFIED",
+		1: "ALERT_CONDITION_ABOVE",
+		2: "ALERT_CONDITION_BELOW",
+	}
+	AlertCondition_value = map[string]int32{
+		"ALERT_CONDITION_UNSPECIFIED": 0,
+		"ALERT_CONDITION_ABOVE":       1,
+		"ALERT_CONDITION_BELOW":       2,
+	}
+)
+
+func (x AlertCondition) Enum() *AlertCondition {
+	p := new(AlertCondition)
+	*p = x
+	return p
+}
+
+func (x AlertCondition) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertCondition) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_service_proto_enumTypes[1].Descriptor()
+}
+
+func (AlertCondition) Type() protoreflect.EnumType {
+	return &file_proto_v2_service_proto_enumTypes[1]
+}
+
+func (x AlertCondition) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertCondition.Descriptor instead.
+func (AlertCondition) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{1}
+}
+
+// Money is an amount of a currency: units are whole units and nanos are
+// billionths of a unit, with the same sign as units. 2800.13 USD is
+// units 2800, nanos 130000000.
+type Money struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 4217 code, or GBX for London listings priced in pence
+	CurrencyCode  string `protobuf:"bytes,1,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	Units         int64  `protobuf:"varint,2,opt,name=units,proto3" json:"units,omitempty"`
+	Nanos         int32  `protobuf:"varint,3,opt,name=nanos,proto3" json:"nanos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_proto_v2_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *Money) GetUnits() int64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *Money) GetNanos() int32 {
+	if x != nil {
+		return x.Nanos
+	}
+	return 0
+}
+
+type FetchPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPriceRequest) Reset() {
+	*x = FetchPriceRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPriceRequest) ProtoMessage() {}
+
+func (x *FetchPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPriceRequest.ProtoReflect.Descriptor instead.
+func (*FetchPriceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FetchPriceRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type FetchPriceResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Price  *Money                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Source string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Stale  bool                   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	// When the price was fetched upstream
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPriceResponse) Reset() {
+	*x = FetchPriceResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPriceResponse) ProtoMessage() {}
+
+func (x *FetchPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPriceResponse.ProtoReflect.Descriptor instead.
+func (*FetchPriceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FetchPriceResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *FetchPriceResponse) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *FetchPriceResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FetchPriceResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *FetchPriceResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type FetchPricesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 50 tickers
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	// Currency to convert prices to, e.g. EUR. Empty leaves each price in
+	// the currency its ticker trades in.
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPricesRequest) Reset() {
+	*x = FetchPricesRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPricesRequest) ProtoMessage() {}
+
+func (x *FetchPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPricesRequest.ProtoReflect.Descriptor instead.
+func (*FetchPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FetchPricesRequest) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+func (x *FetchPricesRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type TickerPrice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Price         *Money                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Stale         bool                   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickerPrice) Reset() {
+	*x = TickerPrice{}
+	mi := &file_proto_v2_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickerPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickerPrice) ProtoMessage() {}
+
+func (x *TickerPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickerPrice.ProtoReflect.Descriptor instead.
+func (*TickerPrice) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TickerPrice) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *TickerPrice) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *TickerPrice) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TickerPrice) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *TickerPrice) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type FetchPricesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tickers without a price are left out
+	Prices []*TickerPrice `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	// Tickers whose price could not be converted to the requested currency
+	Errors        []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPricesResponse) Reset() {
+	*x = FetchPricesResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPricesResponse) ProtoMessage() {}
+
+func (x *FetchPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPricesResponse.ProtoReflect.Descriptor instead.
+func (*FetchPricesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FetchPricesResponse) GetPrices() []*TickerPrice {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *FetchPricesResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type StreamPricesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Tickers []string               `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	// Seconds between prices, 5 by default. The server bounds the intervals
+	// it accepts.
+	IntervalSeconds int32      `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Mode            StreamMode `protobuf:"varint,3,opt,name=mode,proto3,enum=pricefetcher.v2.StreamMode" json:"mode,omitempty"`
+	// For STREAM_MODE_MIN_DELTA: the move from the last price sent that is
+	// sent, as an amount in the ticker's currency or a percentage. Set
+	// exactly one.
+	MinDeltaAmount  float64 `protobuf:"fixed64,4,opt,name=min_delta_amount,json=minDeltaAmount,proto3" json:"min_delta_amount,omitempty"`
+	MinDeltaPercent float64 `protobuf:"fixed64,5,opt,name=min_delta_percent,json=minDeltaPercent,proto3" json:"min_delta_percent,omitempty"`
+	// How long the stream may be idle before a keepalive is sent. Defaults
+	// to 30 for STREAM_MODE_ON_CHANGE and STREAM_MODE_MIN_DELTA; every tick
+	// streams send none unless set.
+	KeepaliveSeconds int32 `protobuf:"varint,6,opt,name=keepalive_seconds,json=keepaliveSeconds,proto3" json:"keepalive_seconds,omitempty"`
+	// Poll intervals for some of the tickers, overriding interval_seconds
+	TickerIntervalSeconds map[string]int32 `protobuf:"bytes,7,rep,name=ticker_interval_seconds,json=tickerIntervalSeconds,proto3" json:"ticker_interval_seconds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Sends each ticker's current price right away instead of after its
+	// first interval
+	SnapshotFirst bool `protobuf:"varint,8,opt,name=snapshot_first,json=snapshotFirst,proto3" json:"snapshot_first,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPricesRequest) Reset() {
+	*x = StreamPricesRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPricesRequest) ProtoMessage() {}
+
+func (x *StreamPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPricesRequest.ProtoReflect.Descriptor instead.
+func (*StreamPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamPricesRequest) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+func (x *StreamPricesRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetMode() StreamMode {
+	if x != nil {
+		return x.Mode
+	}
+	return StreamMode_STREAM_MODE_UNSPECIFIED
+}
+
+func (x *StreamPricesRequest) GetMinDeltaAmount() float64 {
+	if x != nil {
+		return x.MinDeltaAmount
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetMinDeltaPercent() float64 {
+	if x != nil {
+		return x.MinDeltaPercent
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetKeepaliveSeconds() int32 {
+	if x != nil {
+		return x.KeepaliveSeconds
+	}
+	return 0
+}
+
+func (x *StreamPricesRequest) GetTickerIntervalSeconds() map[string]int32 {
+	if x != nil {
+		return x.TickerIntervalSeconds
+	}
+	return nil
+}
+
+func (x *StreamPricesRequest) GetSnapshotFirst() bool {
+	if x != nil {
+		return x.SnapshotFirst
+	}
+	return false
+}
+
+type StreamPricesResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Price  *Money                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	// When the update was sent
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Stale     bool                   `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	// The last price sent for the ticker and the move since, unset on its
+	// first price
+	PreviousPrice *Money  `protobuf:"bytes,7,opt,name=previous_price,json=previousPrice,proto3" json:"previous_price,omitempty"`
+	Change        *Money  `protobuf:"bytes,8,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64 `protobuf:"fixed64,9,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	// Keepalives carry only the timestamp
+	Keepalive     bool `protobuf:"varint,10,opt,name=keepalive,proto3" json:"keepalive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPricesResponse) Reset() {
+	*x = StreamPricesResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPricesResponse) ProtoMessage() {}
+
+func (x *StreamPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPricesResponse.ProtoReflect.Descriptor instead.
+func (*StreamPricesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamPricesResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *StreamPricesResponse) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *StreamPricesResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *StreamPricesResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *StreamPricesResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *StreamPricesResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *StreamPricesResponse) GetPreviousPrice() *Money {
+	if x != nil {
+		return x.PreviousPrice
+	}
+	return nil
+}
+
+func (x *StreamPricesResponse) GetChange() *Money {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *StreamPricesResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *StreamPricesResponse) GetKeepalive() bool {
+	if x != nil {
+		return x.Keepalive
+	}
+	return false
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Echoed in the ack of this request
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Action:
+	//
+	//	*SubscribeRequest_Subscribe
+	//	*SubscribeRequest_Unsubscribe
+	Action        isSubscribeRequest_Action `protobuf_oneof:"action"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubscribeRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetAction() isSubscribeRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetSubscribe() *TickerList {
+	if x != nil {
+		if x, ok := x.Action.(*SubscribeRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetUnsubscribe() *TickerList {
+	if x != nil {
+		if x, ok := x.Action.(*SubscribeRequest_Unsubscribe); ok {
+			return x.Unsubscribe
+		}
+	}
+	return nil
+}
+
+type isSubscribeRequest_Action interface {
+	isSubscribeRequest_Action()
+}
+
+type SubscribeRequest_Subscribe struct {
+	Subscribe *TickerList `protobuf:"bytes,2,opt,name=subscribe,proto3,oneof"`
+}
+
+type SubscribeRequest_Unsubscribe struct {
+	Unsubscribe *TickerList `protobuf:"bytes,3,opt,name=unsubscribe,proto3,oneof"`
+}
+
+func (*SubscribeRequest_Subscribe) isSubscribeRequest_Action() {}
+
+func (*SubscribeRequest_Unsubscribe) isSubscribeRequest_Action() {}
+
+type TickerList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickers       []string               `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickerList) Reset() {
+	*x = TickerList{}
+	mi := &file_proto_v2_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickerList) ProtoMessage() {}
+
+func (x *TickerList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickerList.ProtoReflect.Descriptor instead.
+func (*TickerList) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TickerList) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type SubscribeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*SubscribeResponse_Ack
+	//	*SubscribeResponse_Price
+	//	*SubscribeResponse_Error
+	//	*SubscribeResponse_Heartbeat
+	Message       isSubscribeResponse_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubscribeResponse) GetMessage() isSubscribeResponse_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetAck() *SubscriptionAck {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetPrice() *StreamPricesResponse {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Price); ok {
+			return x.Price
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetError() *TickerError {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Message.(*SubscribeResponse_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+type isSubscribeResponse_Message interface {
+	isSubscribeResponse_Message()
+}
+
+type SubscribeResponse_Ack struct {
+	Ack *SubscriptionAck `protobuf:"bytes,1,opt,name=ack,proto3,oneof"`
+}
+
+type SubscribeResponse_Price struct {
+	Price *StreamPricesResponse `protobuf:"bytes,2,opt,name=price,proto3,oneof"`
+}
+
+type SubscribeResponse_Error struct {
+	// Sent when a subscribed ticker's price cannot be fetched, and again
+	// only if the error changes
+	Error *TickerError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+type SubscribeResponse_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,4,opt,name=heartbeat,proto3,oneof"`
+}
+
+func (*SubscribeResponse_Ack) isSubscribeResponse_Message() {}
+
+func (*SubscribeResponse_Price) isSubscribeResponse_Message() {}
+
+func (*SubscribeResponse_Error) isSubscribeResponse_Message() {}
+
+func (*SubscribeResponse_Heartbeat) isSubscribeResponse_Message() {}
+
+type SubscriptionAck struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Every ticker subscribed to after the change
+	Tickers []string `protobuf:"bytes,2,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	// Tickers of the request that were not subscribed to
+	Errors        []*TickerError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionAck) Reset() {
+	*x = SubscriptionAck{}
+	mi := &file_proto_v2_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionAck) ProtoMessage() {}
+
+func (x *SubscriptionAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionAck.ProtoReflect.Descriptor instead.
+func (*SubscriptionAck) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscriptionAck) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SubscriptionAck) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+func (x *SubscriptionAck) GetErrors() []*TickerError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type TickerError struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	// The gRPC status code name, such as NotFound
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickerError) Reset() {
+	*x = TickerError{}
+	mi := &file_proto_v2_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickerError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickerError) ProtoMessage() {}
+
+func (x *TickerError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickerError.ProtoReflect.Descriptor instead.
+func (*TickerError) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TickerError) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *TickerError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TickerError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Heartbeats are sent while the stream is otherwise idle
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_proto_v2_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Heartbeat) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type FetchQuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchQuoteRequest) Reset() {
+	*x = FetchQuoteRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchQuoteRequest) ProtoMessage() {}
+
+func (x *FetchQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchQuoteRequest.ProtoReflect.Descriptor instead.
+func (*FetchQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FetchQuoteRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type FetchQuoteResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Open   *Money                 `protobuf:"bytes,2,opt,name=open,proto3" json:"open,omitempty"`
+	High   *Money                 `protobuf:"bytes,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low    *Money                 `protobuf:"bytes,4,opt,name=low,proto3" json:"low,omitempty"`
+	Price  *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Volume int64                  `protobuf:"varint,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	// YYYY-MM-DD
+	LatestTradingDay string `protobuf:"bytes,7,opt,name=latest_trading_day,json=latestTradingDay,proto3" json:"latest_trading_day,omitempty"`
+	PreviousClose    *Money `protobuf:"bytes,8,opt,name=previous_close,json=previousClose,proto3" json:"previous_close,omitempty"`
+	Change           *Money `protobuf:"bytes,9,opt,name=change,proto3" json:"change,omitempty"`
+	// Percent change on the previous close, so 1.5 means a 1.5% gain
+	ChangePercent float64                `protobuf:"fixed64,10,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	Source        string                 `protobuf:"bytes,11,opt,name=source,proto3" json:"source,omitempty"`
+	Stale         bool                   `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchQuoteResponse) Reset() {
+	*x = FetchQuoteResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchQuoteResponse) ProtoMessage() {}
+
+func (x *FetchQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchQuoteResponse.ProtoReflect.Descriptor instead.
+func (*FetchQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FetchQuoteResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetOpen() *Money {
+	if x != nil {
+		return x.Open
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetHigh() *Money {
+	if x != nil {
+		return x.High
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetLow() *Money {
+	if x != nil {
+		return x.Low
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetLatestTradingDay() string {
+	if x != nil {
+		return x.LatestTradingDay
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetPreviousClose() *Money {
+	if x != nil {
+		return x.PreviousClose
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetChange() *Money {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *FetchQuoteResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *FetchQuoteResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FetchQuoteResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *FetchQuoteResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type FetchPriceHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	// daily (default), 1min, 5min, 15min, 30min or 60min
+	Interval string `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// daily (default), weekly, monthly or quarterly; daily bars only
+	Period string `protobuf:"bytes,3,opt,name=period,proto3" json:"period,omitempty"`
+	// Scale open, high, low and close for splits and dividends; daily bars only
+	Adjusted bool `protobuf:"varint,4,opt,name=adjusted,proto3" json:"adjusted,omitempty"`
+	// YYYY-MM-DD, or RFC3339 times for intraday bars
+	From string `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	// Currency to convert bars to, e.g. EUR
+	Currency      string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPriceHistoryRequest) Reset() {
+	*x = FetchPriceHistoryRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPriceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPriceHistoryRequest) ProtoMessage() {}
+
+func (x *FetchPriceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPriceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*FetchPriceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FetchPriceHistoryRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryRequest) GetAdjusted() bool {
+	if x != nil {
+		return x.Adjusted
+	}
+	return false
+}
+
+func (x *FetchPriceHistoryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Bar struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// YYYY-MM-DD trading date of the bar
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Start of intraday bars; unset for daily bars
+	Time             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Open             *Money                 `protobuf:"bytes,3,opt,name=open,proto3" json:"open,omitempty"`
+	High             *Money                 `protobuf:"bytes,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low              *Money                 `protobuf:"bytes,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close            *Money                 `protobuf:"bytes,6,opt,name=close,proto3" json:"close,omitempty"`
+	Volume           int64                  `protobuf:"varint,7,opt,name=volume,proto3" json:"volume,omitempty"`
+	AdjustedClose    *Money                 `protobuf:"bytes,8,opt,name=adjusted_close,json=adjustedClose,proto3" json:"adjusted_close,omitempty"`
+	DividendAmount   *Money                 `protobuf:"bytes,9,opt,name=dividend_amount,json=dividendAmount,proto3" json:"dividend_amount,omitempty"`
+	SplitCoefficient float64                `protobuf:"fixed64,10,opt,name=split_coefficient,json=splitCoefficient,proto3" json:"split_coefficient,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Bar) Reset() {
+	*x = Bar{}
+	mi := &file_proto_v2_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bar) ProtoMessage() {}
+
+func (x *Bar) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bar.ProtoReflect.Descriptor instead.
+func (*Bar) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Bar) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Bar) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Bar) GetOpen() *Money {
+	if x != nil {
+		return x.Open
+	}
+	return nil
+}
+
+func (x *Bar) GetHigh() *Money {
+	if x != nil {
+		return x.High
+	}
+	return nil
+}
+
+func (x *Bar) GetLow() *Money {
+	if x != nil {
+		return x.Low
+	}
+	return nil
+}
+
+func (x *Bar) GetClose() *Money {
+	if x != nil {
+		return x.Close
+	}
+	return nil
+}
+
+func (x *Bar) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Bar) GetAdjustedClose() *Money {
+	if x != nil {
+		return x.AdjustedClose
+	}
+	return nil
+}
+
+func (x *Bar) GetDividendAmount() *Money {
+	if x != nil {
+		return x.DividendAmount
+	}
+	return nil
+}
+
+func (x *Bar) GetSplitCoefficient() float64 {
+	if x != nil {
+		return x.SplitCoefficient
+	}
+	return 0
+}
+
+// Every chunk repeats the fields describing the series
+type FetchPriceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Period        string                 `protobuf:"bytes,3,opt,name=period,proto3" json:"period,omitempty"`
+	Adjusted      bool                   `protobuf:"varint,4,opt,name=adjusted,proto3" json:"adjusted,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Stale         bool                   `protobuf:"varint,6,opt,name=stale,proto3" json:"stale,omitempty"`
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	Bars          []*Bar                 `protobuf:"bytes,8,rep,name=bars,proto3" json:"bars,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchPriceHistoryResponse) Reset() {
+	*x = FetchPriceHistoryResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchPriceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchPriceHistoryResponse) ProtoMessage() {}
+
+func (x *FetchPriceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchPriceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*FetchPriceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FetchPriceHistoryResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryResponse) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryResponse) GetAdjusted() bool {
+	if x != nil {
+		return x.Adjusted
+	}
+	return false
+}
+
+func (x *FetchPriceHistoryResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FetchPriceHistoryResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *FetchPriceHistoryResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *FetchPriceHistoryResponse) GetBars() []*Bar {
+	if x != nil {
+		return x.Bars
+	}
+	return nil
+}
+
+type Alert struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ticker     string                 `protobuf:"bytes,2,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Condition  AlertCondition         `protobuf:"varint,3,opt,name=condition,proto3,enum=pricefetcher.v2.AlertCondition" json:"condition,omitempty"`
+	Threshold  *Money                 `protobuf:"bytes,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	WebhookUrl string                 `protobuf:"bytes,5,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	Active     bool                   `protobuf:"varint,6,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Unset until the alert fires
+	TriggeredAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=triggered_at,json=triggeredAt,proto3" json:"triggered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_proto_v2_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Alert) GetCondition() AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return AlertCondition_ALERT_CONDITION_UNSPECIFIED
+}
+
+func (x *Alert) GetThreshold() *Money {
+	if x != nil {
+		return x.Threshold
+	}
+	return nil
+}
+
+func (x *Alert) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *Alert) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Alert) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetTriggeredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TriggeredAt
+	}
+	return nil
+}
+
+type CreateAlertRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Ticker    string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Condition AlertCondition         `protobuf:"varint,2,opt,name=condition,proto3,enum=pricefetcher.v2.AlertCondition" json:"condition,omitempty"`
+	// In the currency the ticker trades in. The currency code may be left
+	// empty.
+	Threshold     *Money `protobuf:"bytes,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	WebhookUrl    string `protobuf:"bytes,4,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlertRequest) Reset() {
+	*x = CreateAlertRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRequest) ProtoMessage() {}
+
+func (x *CreateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateAlertRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *CreateAlertRequest) GetCondition() AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return AlertCondition_ALERT_CONDITION_UNSPECIFIED
+}
+
+func (x *CreateAlertRequest) GetThreshold() *Money {
+	if x != nil {
+		return x.Threshold
+	}
+	return nil
+}
+
+func (x *CreateAlertRequest) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+type GetAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertRequest) Reset() {
+	*x = GetAlertRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRequest) ProtoMessage() {}
+
+func (x *GetAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetAlertRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListAlertsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{22}
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type DeleteAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertRequest) Reset() {
+	*x = DeleteAlertRequest{}
+	mi := &file_proto_v2_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRequest) ProtoMessage() {}
+
+func (x *DeleteAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteAlertRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertResponse) Reset() {
+	*x = DeleteAlertResponse{}
+	mi := &file_proto_v2_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertResponse) ProtoMessage() {}
+
+func (x *DeleteAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_service_proto_rawDescGZIP(), []int{25}
+}
+
+var File_proto_v2_service_proto protoreflect.FileDescriptor
+
+const file_proto_v2_service_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/v2/service.proto\x12\x0fpricefetcher.v2\x1a\x1fgoogle/protobuf/timestamp.proto\"X\n" +
+	"\x05Money\x12#\n" +
+	"\rcurrency_code\x18\x01 \x01(\tR\fcurrencyCode\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\x03R\x05units\x12\x14\n" +
+	"\x05nanos\x18\x03 \x01(\x05R\x05nanos\"+\n" +
+	"\x11FetchPriceRequest\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\"\xb9\x01\n" +
+	"\x12FetchPriceResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12,\n" +
+	"\x05price\x18\x02 \x01(\v2\x16.pricefetcher.v2.MoneyR\x05price\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12/\n" +
+	"\x05as_of\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"J\n" +
+	"\x12FetchPricesRequest\x12\x18\n" +
+	"\atickers\x18\x01 \x03(\tR\atickers\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xb2\x01\n" +
+	"\vTickerPrice\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12,\n" +
+	"\x05price\x18\x02 \x01(\v2\x16.pricefetcher.v2.MoneyR\x05price\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12/\n" +
+	"\x05as_of\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"c\n" +
+	"\x13FetchPricesResponse\x124\n" +
+	"\x06prices\x18\x01 \x03(\v2\x1c.pricefetcher.v2.TickerPriceR\x06prices\x12\x16\n" +
+	"\x06errors\x18\x02 \x03(\tR\x06errors\"\xf8\x03\n" +
+	"\x13StreamPricesRequest\x12\x18\n" +
+	"\atickers\x18\x01 \x03(\tR\atickers\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\x12/\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x1b.pricefetcher.v2.StreamModeR\x04mode\x12(\n" +
+	"\x10min_delta_amount\x18\x04 \x01(\x01R\x0eminDeltaAmount\x12*\n" +
+	"\x11min_delta_percent\x18\x05 \x01(\x01R\x0fminDeltaPercent\x12+\n" +
+	"\x11keepalive_seconds\x18\x06 \x01(\x05R\x10keepaliveSeconds\x12w\n" +
+	"\x17ticker_interval_seconds\x18\a \x03(\v2?.pricefetcher.v2.StreamPricesRequest.TickerIntervalSecondsEntryR\x15tickerIntervalSeconds\x12%\n" +
+	"\x0esnapshot_first\x18\b \x01(\bR\rsnapshotFirst\x1aH\n" +
+	"\x1aTickerIntervalSecondsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xa9\x03\n" +
+	"\x14StreamPricesResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12,\n" +
+	"\x05price\x18\x02 \x01(\v2\x16.pricefetcher.v2.MoneyR\x05price\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x05 \x01(\bR\x05stale\x12/\n" +
+	"\x05as_of\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\x12=\n" +
+	"\x0eprevious_price\x18\a \x01(\v2\x16.pricefetcher.v2.MoneyR\rpreviousPrice\x12.\n" +
+	"\x06change\x18\b \x01(\v2\x16.pricefetcher.v2.MoneyR\x06change\x12%\n" +
+	"\x0echange_percent\x18\t \x01(\x01R\rchangePercent\x12\x1c\n" +
+	"\tkeepalive\x18\n" +
+	" \x01(\bR\tkeepalive\"\xb9\x01\n" +
+	"\x10SubscribeRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12;\n" +
+	"\tsubscribe\x18\x02 \x01(\v2\x1b.pricefetcher.v2.TickerListH\x00R\tsubscribe\x12?\n" +
+	"\vunsubscribe\x18\x03 \x01(\v2\x1b.pricefetcher.v2.TickerListH\x00R\vunsubscribeB\b\n" +
+	"\x06action\"&\n" +
+	"\n" +
+	"TickerList\x12\x18\n" +
+	"\atickers\x18\x01 \x03(\tR\atickers\"\x85\x02\n" +
+	"\x11SubscribeResponse\x124\n" +
+	"\x03ack\x18\x01 \x01(\v2 .pricefetcher.v2.SubscriptionAckH\x00R\x03ack\x12=\n" +
+	"\x05price\x18\x02 \x01(\v2%.pricefetcher.v2.StreamPricesResponseH\x00R\x05price\x124\n" +
+	"\x05error\x18\x03 \x01(\v2\x1c.pricefetcher.v2.TickerErrorH\x00R\x05error\x12:\n" +
+	"\theartbeat\x18\x04 \x01(\v2\x1a.pricefetcher.v2.HeartbeatH\x00R\theartbeatB\t\n" +
+	"\amessage\"\x80\x01\n" +
+	"\x0fSubscriptionAck\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x18\n" +
+	"\atickers\x18\x02 \x03(\tR\atickers\x124\n" +
+	"\x06errors\x18\x03 \x03(\v2\x1c.pricefetcher.v2.TickerErrorR\x06errors\"S\n" +
+	"\vTickerError\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"E\n" +
+	"\tHeartbeat\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"+\n" +
+	"\x11FetchQuoteRequest\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\"\x97\x04\n" +
+	"\x12FetchQuoteResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12*\n" +
+	"\x04open\x18\x02 \x01(\v2\x16.pricefetcher.v2.MoneyR\x04open\x12*\n" +
+	"\x04high\x18\x03 \x01(\v2\x16.pricefetcher.v2.MoneyR\x04high\x12(\n" +
+	"\x03low\x18\x04 \x01(\v2\x16.pricefetcher.v2.MoneyR\x03low\x12,\n" +
+	"\x05price\x18\x05 \x01(\v2\x16.pricefetcher.v2.MoneyR\x05price\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x03R\x06volume\x12,\n" +
+	"\x12latest_trading_day\x18\a \x01(\tR\x10latestTradingDay\x12=\n" +
+	"\x0eprevious_close\x18\b \x01(\v2\x16.pricefetcher.v2.MoneyR\rpreviousClose\x12.\n" +
+	"\x06change\x18\t \x01(\v2\x16.pricefetcher.v2.MoneyR\x06change\x12%\n" +
+	"\x0echange_percent\x18\n" +
+	" \x01(\x01R\rchangePercent\x12\x16\n" +
+	"\x06source\x18\v \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\x12/\n" +
+	"\x05as_of\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xc2\x01\n" +
+	"\x18FetchPriceHistoryRequest\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x16\n" +
+	"\x06period\x18\x03 \x01(\tR\x06period\x12\x1a\n" +
+	"\badjusted\x18\x04 \x01(\bR\badjusted\x12\x12\n" +
+	"\x04from\x18\x05 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x06 \x01(\tR\x02to\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"\xbe\x03\n" +
+	"\x03Bar\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12*\n" +
+	"\x04open\x18\x03 \x01(\v2\x16.pricefetcher.v2.MoneyR\x04open\x12*\n" +
+	"\x04high\x18\x04 \x01(\v2\x16.pricefetcher.v2.MoneyR\x04high\x12(\n" +
+	"\x03low\x18\x05 \x01(\v2\x16.pricefetcher.v2.MoneyR\x03low\x12,\n" +
+	"\x05close\x18\x06 \x01(\v2\x16.pricefetcher.v2.MoneyR\x05close\x12\x16\n" +
+	"\x06volume\x18\a \x01(\x03R\x06volume\x12=\n" +
+	"\x0eadjusted_close\x18\b \x01(\v2\x16.pricefetcher.v2.MoneyR\radjustedClose\x12?\n" +
+	"\x0fdividend_amount\x18\t \x01(\v2\x16.pricefetcher.v2.MoneyR\x0edividendAmount\x12+\n" +
+	"\x11split_coefficient\x18\n" +
+	" \x01(\x01R\x10splitCoefficient\"\x8c\x02\n" +
+	"\x19FetchPriceHistoryResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x16\n" +
+	"\x06period\x18\x03 \x01(\tR\x06period\x12\x1a\n" +
+	"\badjusted\x18\x04 \x01(\bR\badjusted\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x14\n" +
+	"\x05stale\x18\x06 \x01(\bR\x05stale\x12/\n" +
+	"\x05as_of\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\x12(\n" +
+	"\x04bars\x18\b \x03(\v2\x14.pricefetcher.v2.BarR\x04bars\"\xd7\x02\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06ticker\x18\x02 \x01(\tR\x06ticker\x12=\n" +
+	"\tcondition\x18\x03 \x01(\x0e2\x1f.pricefetcher.v2.AlertConditionR\tcondition\x124\n" +
+	"\tthreshold\x18\x04 \x01(\v2\x16.pricefetcher.v2.MoneyR\tthreshold\x12\x1f\n" +
+	"\vwebhook_url\x18\x05 \x01(\tR\n" +
+	"webhookUrl\x12\x16\n" +
+	"\x06active\x18\x06 \x01(\bR\x06active\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\ftriggered_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vtriggeredAt\"\xc2\x01\n" +
+	"\x12CreateAlertRequest\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12=\n" +
+	"\tcondition\x18\x02 \x01(\x0e2\x1f.pricefetcher.v2.AlertConditionR\tcondition\x124\n" +
+	"\tthreshold\x18\x03 \x01(\v2\x16.pricefetcher.v2.MoneyR\tthreshold\x12\x1f\n" +
+	"\vwebhook_url\x18\x04 \x01(\tR\n" +
+	"webhookUrl\"!\n" +
+	"\x0fGetAlertRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11ListAlertsRequest\"D\n" +
+	"\x12ListAlertsResponse\x12.\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x16.pricefetcher.v2.AlertR\x06alerts\"$\n" +
+	"\x12DeleteAlertRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13DeleteAlertResponse*{\n" +
+	"\n" +
+	"StreamMode\x12\x1b\n" +
+	"\x17STREAM_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16STREAM_MODE_EVERY_TICK\x10\x01\x12\x19\n" +
+	"\x15STREAM_MODE_ON_CHANGE\x10\x02\x12\x19\n" +
+	"\x15STREAM_MODE_MIN_DELTA\x10\x03*g\n" +
+	"\x0eAlertCondition\x12\x1f\n" +
+	"\x1bALERT_CONDITION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ALERT_CONDITION_ABOVE\x10\x01\x12\x19\n" +
+	"\x15ALERT_CONDITION_BELOW\x10\x022\xfe\x06\n" +
+	"\fPriceFetcher\x12U\n" +
+	"\n" +
+	"FetchPrice\x12\".pricefetcher.v2.FetchPriceRequest\x1a#.pricefetcher.v2.FetchPriceResponse\x12X\n" +
+	"\vFetchPrices\x12#.pricefetcher.v2.FetchPricesRequest\x1a$.pricefetcher.v2.FetchPricesResponse\x12]\n" +
+	"\fStreamPrices\x12$.pricefetcher.v2.StreamPricesRequest\x1a%.pricefetcher.v2.StreamPricesResponse0\x01\x12V\n" +
+	"\tSubscribe\x12!.pricefetcher.v2.SubscribeRequest\x1a\".pricefetcher.v2.SubscribeResponse(\x010\x01\x12U\n" +
+	"\n" +
+	"FetchQuote\x12\".pricefetcher.v2.FetchQuoteRequest\x1a#.pricefetcher.v2.FetchQuoteResponse\x12l\n" +
+	"\x11FetchPriceHistory\x12).pricefetcher.v2.FetchPriceHistoryRequest\x1a*.pricefetcher.v2.FetchPriceHistoryResponse0\x01\x12J\n" +
+	"\vCreateAlert\x12#.pricefetcher.v2.CreateAlertRequest\x1a\x16.pricefetcher.v2.Alert\x12D\n" +
+	"\bGetAlert\x12 .pricefetcher.v2.GetAlertRequest\x1a\x16.pricefetcher.v2.Alert\x12U\n" +
+	"\n" +
+	"ListAlerts\x12\".pricefetcher.v2.ListAlertsRequest\x1a#.pricefetcher.v2.ListAlertsResponse\x12X\n" +
+	"\vDeleteAlert\x12#.pricefetcher.v2.DeleteAlertRequest\x1a$.pricefetcher.v2.DeleteAlertResponseB4Z2github.com/aliexe/ms-priceFetcher/proto/v2;protov2b\x06proto3"
+
+var (
+	file_proto_v2_service_proto_rawDescOnce sync.Once
+	file_proto_v2_service_proto_rawDescData []byte
+)
+
+func file_proto_v2_service_proto_rawDescGZIP() []byte {
+	file_proto_v2_service_proto_rawDescOnce.Do(func() {
+		file_proto_v2_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_v2_service_proto_rawDesc), len(file_proto_v2_service_proto_rawDesc)))
+	})
+	return file_proto_v2_service_proto_rawDescData
+}
+
+var file_proto_v2_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_v2_service_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_proto_v2_service_proto_goTypes = []any{
+	(StreamMode)(0),                   // 0: pricefetcher.v2.StreamMode
+	(AlertCondition)(0),               // 1: pricefetcher.v2.AlertCondition
+	(*Money)(nil),                     // 2: pricefetcher.v2.Money
+	(*FetchPriceRequest)(nil),         // 3: pricefetcher.v2.FetchPriceRequest
+	(*FetchPriceResponse)(nil),        // 4: pricefetcher.v2.FetchPriceResponse
+	(*FetchPricesRequest)(nil),        // 5: pricefetcher.v2.FetchPricesRequest
+	(*TickerPrice)(nil),               // 6: pricefetcher.v2.TickerPrice
+	(*FetchPricesResponse)(nil),       // 7: pricefetcher.v2.FetchPricesResponse
+	(*StreamPricesRequest)(nil),       // 8: pricefetcher.v2.StreamPricesRequest
+	(*StreamPricesResponse)(nil),      // 9: pricefetcher.v2.StreamPricesResponse
+	(*SubscribeRequest)(nil),          // 10: pricefetcher.v2.SubscribeRequest
+	(*TickerList)(nil),                // 11: pricefetcher.v2.TickerList
+	(*SubscribeResponse)(nil),         // 12: pricefetcher.v2.SubscribeResponse
+	(*SubscriptionAck)(nil),           // 13: pricefetcher.v2.SubscriptionAck
+	(*TickerError)(nil),               // 14: pricefetcher.v2.TickerError
+	(*Heartbeat)(nil),                 // 15: pricefetcher.v2.Heartbeat
+	(*FetchQuoteRequest)(nil),         // 16: pricefetcher.v2.FetchQuoteRequest
+	(*FetchQuoteResponse)(nil),        // 17: pricefetcher.v2.FetchQuoteResponse
+	(*FetchPriceHistoryRequest)(nil),  // 18: pricefetcher.v2.FetchPriceHistoryRequest
+	(*Bar)(nil),                       // 19: pricefetcher.v2.Bar
+	(*FetchPriceHistoryResponse)(nil), // 20: pricefetcher.v2.FetchPriceHistoryResponse
+	(*Alert)(nil),                     // 21: pricefetcher.v2.Alert
+	(*CreateAlertRequest)(nil),        // 22: pricefetcher.v2.CreateAlertRequest
+	(*GetAlertRequest)(nil),           // 23: pricefetcher.v2.GetAlertRequest
+	(*ListAlertsRequest)(nil),         // 24: pricefetcher.v2.ListAlertsRequest
+	(*ListAlertsResponse)(nil),        // 25: pricefetcher.v2.ListAlertsResponse
+	(*DeleteAlertRequest)(nil),        // 26: pricefetcher.v2.DeleteAlertRequest
+	(*DeleteAlertResponse)(nil),       // 27: pricefetcher.v2.DeleteAlertResponse
+	nil,                               // 28: pricefetcher.v2.StreamPricesRequest.TickerIntervalSecondsEntry
+	(*timestamppb.Timestamp)(nil),     // 29: google.protobuf.Timestamp
+}
+var file_proto_v2_service_proto_depIdxs = []int32{
+	2,  // 0: pricefetcher.v2.FetchPriceResponse.price:type_name -> pricefetcher.v2.Money
+	29, // 1: pricefetcher.v2.FetchPriceResponse.as_of:type_name -> google.protobuf.Timestamp
+	2,  // 2: pricefetcher.v2.TickerPrice.price:type_name -> pricefetcher.v2.Money
+	29, // 3: pricefetcher.v2.TickerPrice.as_of:type_name -> google.protobuf.Timestamp
+	6,  // 4: pricefetcher.v2.FetchPricesResponse.prices:type_name -> pricefetcher.v2.TickerPrice
+	0,  // 5: pricefetcher.v2.StreamPricesRequest.mode:type_name -> pricefetcher.v2.StreamMode
+	28, // 6: pricefetcher.v2.StreamPricesRequest.ticker_interval_seconds:type_name -> pricefetcher.v2.StreamPricesRequest.TickerIntervalSecondsEntry
+	2,  // 7: pricefetcher.v2.StreamPricesResponse.price:type_name -> pricefetcher.v2.Money
+	29, // 8: pricefetcher.v2.StreamPricesResponse.timestamp:type_name -> google.protobuf.Timestamp
+	29, // 9: pricefetcher.v2.StreamPricesResponse.as_of:type_name -> google.protobuf.Timestamp
+	2,  // 10: pricefetcher.v2.StreamPricesResponse.previous_price:type_name -> pricefetcher.v2.Money
+	2,  // 11: pricefetcher.v2.StreamPricesResponse.change:type_name -> pricefetcher.v2.Money
+	11, // 12: pricefetcher.v2.SubscribeRequest.subscribe:type_name -> pricefetcher.v2.TickerList
+	11, // 13: pricefetcher.v2.SubscribeRequest.unsubscribe:type_name -> pricefetcher.v2.TickerList
+	13, // 14: pricefetcher.v2.SubscribeResponse.ack:type_name -> pricefetcher.v2.SubscriptionAck
+	9,  // 15: pricefetcher.v2.SubscribeResponse.price:type_name -> pricefetcher.v2.StreamPricesResponse
+	14, // 16: pricefetcher.v2.SubscribeResponse.error:type_name -> pricefetcher.v2.TickerError
+	15, // 17: pricefetcher.v2.SubscribeResponse.heartbeat:type_name -> pricefetcher.v2.Heartbeat
+	14, // 18: pricefetcher.v2.SubscriptionAck.errors:type_name -> pricefetcher.v2.TickerError
+	29, // 19: pricefetcher.v2.Heartbeat.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 20: pricefetcher.v2.FetchQuoteResponse.open:type_name -> pricefetcher.v2.Money
+	2,  // 21: pricefetcher.v2.FetchQuoteResponse.high:type_name -> pricefetcher.v2.Money
+	2,  // 22: pricefetcher.v2.FetchQuoteResponse.low:type_name -> pricefetcher.v2.Money
+	2,  // 23: pricefetcher.v2.FetchQuoteResponse.price:type_name -> pricefetcher.v2.Money
+	2,  // 24: pricefetcher.v2.FetchQuoteResponse.previous_close:type_name -> pricefetcher.v2.Money
+	2,  // 25: pricefetcher.v2.FetchQuoteResponse.change:type_name -> pricefetcher.v2.Money
+	29, // 26: pricefetcher.v2.FetchQuoteResponse.as_of:type_name -> google.protobuf.Timestamp
+	29, // 27: pricefetcher.v2.Bar.time:type_name -> google.protobuf.Timestamp
+	2,  // 28: pricefetcher.v2.Bar.open:type_name -> pricefetcher.v2.Money
+	2,  // 29: pricefetcher.v2.Bar.high:type_name -> pricefetcher.v2.Money
+	2,  // 30: pricefetcher.v2.Bar.low:type_name -> pricefetcher.v2.Money
+	2,  // 31: pricefetcher.v2.Bar.close:type_name -> pricefetcher.v2.Money
+	2,  // 32: pricefetcher.v2.Bar.adjusted_close:type_name -> pricefetcher.v2.Money
+	2,  // 33: pricefetcher.v2.Bar.dividend_amount:type_name -> pricefetcher.v2.Money
+	29, // 34: pricefetcher.v2.FetchPriceHistoryResponse.as_of:type_name -> google.protobuf.Timestamp
+	19, // 35: pricefetcher.v2.FetchPriceHistoryResponse.bars:type_name -> pricefetcher.v2.Bar
+	1,  // 36: pricefetcher.v2.Alert.condition:type_name -> pricefetcher.v2.AlertCondition
+	2,  // 37: pricefetcher.v2.Alert.threshold:type_name -> pricefetcher.v2.Money
+	29, // 38: pricefetcher.v2.Alert.created_at:type_name -> google.protobuf.Timestamp
+	29, // 39: pricefetcher.v2.Alert.triggered_at:type_name -> google.protobuf.Timestamp
+	1,  // 40: pricefetcher.v2.CreateAlertRequest.condition:type_name -> pricefetcher.v2.AlertCondition
+	2,  // 41: pricefetcher.v2.CreateAlertRequest.threshold:type_name -> pricefetcher.v2.Money
+	21, // 42: pricefetcher.v2.ListAlertsResponse.alerts:type_name -> pricefetcher.v2.Alert
+	3,  // 43: pricefetcher.v2.PriceFetcher.FetchPrice:input_type -> pricefetcher.v2.FetchPriceRequest
+	5,  // 44: pricefetcher.v2.PriceFetcher.FetchPrices:input_type -> pricefetcher.v2.FetchPricesRequest
+	8,  // 45: pricefetcher.v2.PriceFetcher.StreamPrices:input_type -> pricefetcher.v2.StreamPricesRequest
+	10, // 46: pricefetcher.v2.PriceFetcher.Subscribe:input_type -> pricefetcher.v2.SubscribeRequest
+	16, // 47: pricefetcher.v2.PriceFetcher.FetchQuote:input_type -> pricefetcher.v2.FetchQuoteRequest
+	18, // 48: pricefetcher.v2.PriceFetcher.FetchPriceHistory:input_type -> pricefetcher.v2.FetchPriceHistoryRequest
+	22, // 49: pricefetcher.v2.PriceFetcher.CreateAlert:input_type -> pricefetcher.v2.CreateAlertRequest
+	23, // 50: pricefetcher.v2.PriceFetcher.GetAlert:input_type -> pricefetcher.v2.GetAlertRequest
+	24, // 51: pricefetcher.v2.PriceFetcher.ListAlerts:input_type -> pricefetcher.v2.ListAlertsRequest
+	26, // 52: pricefetcher.v2.PriceFetcher.DeleteAlert:input_type -> pricefetcher.v2.DeleteAlertRequest
+	4,  // 53: pricefetcher.v2.PriceFetcher.FetchPrice:output_type -> pricefetcher.v2.FetchPriceResponse
+	7,  // 54: pricefetcher.v2.PriceFetcher.FetchPrices:output_type -> pricefetcher.v2.FetchPricesResponse
+	9,  // 55: pricefetcher.v2.PriceFetcher.StreamPrices:output_type -> pricefetcher.v2.StreamPricesResponse
+	12, // 56: pricefetcher.v2.PriceFetcher.Subscribe:output_type -> pricefetcher.v2.SubscribeResponse
+	17, // 57: pricefetcher.v2.PriceFetcher.FetchQuote:output_type -> pricefetcher.v2.FetchQuoteResponse
+	20, // 58: pricefetcher.v2.PriceFetcher.FetchPriceHistory:output_type -> pricefetcher.v2.FetchPriceHistoryResponse
+	21, // 59: pricefetcher.v2.PriceFetcher.CreateAlert:output_type -> pricefetcher.v2.Alert
+	21, // 60: pricefetcher.v2.PriceFetcher.GetAlert:output_type -> pricefetcher.v2.Alert
+	25, // 61: pricefetcher.v2.PriceFetcher.ListAlerts:output_type -> pricefetcher.v2.ListAlertsResponse
+	27, // 62: pricefetcher.v2.PriceFetcher.DeleteAlert:output_type -> pricefetcher.v2.DeleteAlertResponse
+	53, // [53:63] is the sub-list for method output_type
+	43, // [43:53] is the sub-list for method input_type
+	43, // [43:43] is the sub-list for extension type_name
+	43, // [43:43] is the sub-list for extension extendee
+	0,  // [0:43] is the sub-list for field type_name
+}
+
+func init() { file_proto_v2_service_proto_init() }
+func file_proto_v2_service_proto_init() {
+	if File_proto_v2_service_proto != nil {
+		return
+	}
+	file_proto_v2_service_proto_msgTypes[8].OneofWrappers = []any{
+		(*SubscribeRequest_Subscribe)(nil),
+		(*SubscribeRequest_Unsubscribe)(nil),
+	}
+	file_proto_v2_service_proto_msgTypes[10].OneofWrappers = []any{
+		(*SubscribeResponse_Ack)(nil),
+		(*SubscribeResponse_Price)(nil),
+		(*SubscribeResponse_Error)(nil),
+		(*SubscribeResponse_Heartbeat)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v2_service_proto_rawDesc), len(file_proto_v2_service_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_v2_service_proto_goTypes,
+		DependencyIndexes: file_proto_v2_service_proto_depIdxs,
+		EnumInfos:         file_proto_v2_service_proto_enumTypes,
+		MessageInfos:      file_proto_v2_service_proto_msgTypes,
+	}.Build()
+	File_proto_v2_service_proto = out.File
+	file_proto_v2_service_proto_goTypes = nil
+	file_proto_v2_service_proto_depIdxs = nil
+}
@@ -0,0 +1,474 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: proto/v2/service.proto
+
+// Version 2 of the price fetcher API. Money is exact, as units and nanos of
+// a currency, and times are google.protobuf.Timestamp. Version 1 is still
+// served alongside it.
+
+package protov2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PriceFetcher_FetchPrice_FullMethodName        = "/pricefetcher.v2.PriceFetcher/FetchPrice"
+	PriceFetcher_FetchPrices_FullMethodName       = "/pricefetcher.v2.PriceFetcher/FetchPrices"
+	PriceFetcher_StreamPrices_FullMethodName      = "/pricefetcher.v2.PriceFetcher/StreamPrices"
+	PriceFetcher_Subscribe_FullMethodName         = "/pricefetcher.v2.PriceFetcher/Subscribe"
+	PriceFetcher_FetchQuote_FullMethodName        = "/pricefetcher.v2.PriceFetcher/FetchQuote"
+	PriceFetcher_FetchPriceHistory_FullMethodName = "/pricefetcher.v2.PriceFetcher/FetchPriceHistory"
+	PriceFetcher_CreateAlert_FullMethodName       = "/pricefetcher.v2.PriceFetcher/CreateAlert"
+	PriceFetcher_GetAlert_FullMethodName          = "/pricefetcher.v2.PriceFetcher/GetAlert"
+	PriceFetcher_ListAlerts_FullMethodName        = "/pricefetcher.v2.PriceFetcher/ListAlerts"
+	PriceFetcher_DeleteAlert_FullMethodName       = "/pricefetcher.v2.PriceFetcher/DeleteAlert"
+)
+
+// PriceFetcherClient is the client API for PriceFetcher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PriceFetcherClient interface {
+	FetchPrice(ctx context.Context, in *FetchPriceRequest, opts ...grpc.CallOption) (*FetchPriceResponse, error)
+	FetchPrices(ctx context.Context, in *FetchPricesRequest, opts ...grpc.CallOption) (*FetchPricesResponse, error)
+	StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamPricesResponse], error)
+	// Streams prices for a set of tickers the client changes while the stream
+	// is open. Every subscription change is acknowledged.
+	Subscribe(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubscribeRequest, SubscribeResponse], error)
+	FetchQuote(ctx context.Context, in *FetchQuoteRequest, opts ...grpc.CallOption) (*FetchQuoteResponse, error)
+	// Bars are sent in chunks, so long ranges need not fit in one message
+	FetchPriceHistory(ctx context.Context, in *FetchPriceHistoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FetchPriceHistoryResponse], error)
+	CreateAlert(ctx context.Context, in *CreateAlertRequest, opts ...grpc.CallOption) (*Alert, error)
+	GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*Alert, error)
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	DeleteAlert(ctx context.Context, in *DeleteAlertRequest, opts ...grpc.CallOption) (*DeleteAlertResponse, error)
+}
+
+type priceFetcherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPriceFetcherClient(cc grpc.ClientConnInterface) PriceFetcherClient {
+	return &priceFetcherClient{cc}
+}
+
+func (c *priceFetcherClient) FetchPrice(ctx context.Context, in *FetchPriceRequest, opts ...grpc.CallOption) (*FetchPriceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchPriceResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_FetchPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) FetchPrices(ctx context.Context, in *FetchPricesRequest, opts ...grpc.CallOption) (*FetchPricesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchPricesResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_FetchPrices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamPricesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceFetcher_ServiceDesc.Streams[0], PriceFetcher_StreamPrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPricesRequest, StreamPricesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_StreamPricesClient = grpc.ServerStreamingClient[StreamPricesResponse]
+
+func (c *priceFetcherClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubscribeRequest, SubscribeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceFetcher_ServiceDesc.Streams[1], PriceFetcher_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, SubscribeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_SubscribeClient = grpc.BidiStreamingClient[SubscribeRequest, SubscribeResponse]
+
+func (c *priceFetcherClient) FetchQuote(ctx context.Context, in *FetchQuoteRequest, opts ...grpc.CallOption) (*FetchQuoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchQuoteResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_FetchQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) FetchPriceHistory(ctx context.Context, in *FetchPriceHistoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FetchPriceHistoryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceFetcher_ServiceDesc.Streams[2], PriceFetcher_FetchPriceHistory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchPriceHistoryRequest, FetchPriceHistoryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_FetchPriceHistoryClient = grpc.ServerStreamingClient[FetchPriceHistoryResponse]
+
+func (c *priceFetcherClient) CreateAlert(ctx context.Context, in *CreateAlertRequest, opts ...grpc.CallOption) (*Alert, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Alert)
+	err := c.cc.Invoke(ctx, PriceFetcher_CreateAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*Alert, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Alert)
+	err := c.cc.Invoke(ctx, PriceFetcher_GetAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceFetcherClient) DeleteAlert(ctx context.Context, in *DeleteAlertRequest, opts ...grpc.CallOption) (*DeleteAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlertResponse)
+	err := c.cc.Invoke(ctx, PriceFetcher_DeleteAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PriceFetcherServer is the server API for PriceFetcher service.
+// All implementations must embed UnimplementedPriceFetcherServer
+// for forward compatibility.
+type PriceFetcherServer interface {
+	FetchPrice(context.Context, *FetchPriceRequest) (*FetchPriceResponse, error)
+	FetchPrices(context.Context, *FetchPricesRequest) (*FetchPricesResponse, error)
+	StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[StreamPricesResponse]) error
+	// Streams prices for a set of tickers the client changes while the stream
+	// is open. Every subscription change is acknowledged.
+	Subscribe(grpc.BidiStreamingServer[SubscribeRequest, SubscribeResponse]) error
+	FetchQuote(context.Context, *FetchQuoteRequest) (*FetchQuoteResponse, error)
+	// Bars are sent in chunks, so long ranges need not fit in one message
+	FetchPriceHistory(*FetchPriceHistoryRequest, grpc.ServerStreamingServer[FetchPriceHistoryResponse]) error
+	CreateAlert(context.Context, *CreateAlertRequest) (*Alert, error)
+	GetAlert(context.Context, *GetAlertRequest) (*Alert, error)
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	DeleteAlert(context.Context, *DeleteAlertRequest) (*DeleteAlertResponse, error)
+	mustEmbedUnimplementedPriceFetcherServer()
+}
+
+// UnimplementedPriceFetcherServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPriceFetcherServer struct{}
+
+func (UnimplementedPriceFetcherServer) FetchPrice(context.Context, *FetchPriceRequest) (*FetchPriceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FetchPrice not implemented")
+}
+func (UnimplementedPriceFetcherServer) FetchPrices(context.Context, *FetchPricesRequest) (*FetchPricesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FetchPrices not implemented")
+}
+func (UnimplementedPriceFetcherServer) StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[StreamPricesResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamPrices not implemented")
+}
+func (UnimplementedPriceFetcherServer) Subscribe(grpc.BidiStreamingServer[SubscribeRequest, SubscribeResponse]) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedPriceFetcherServer) FetchQuote(context.Context, *FetchQuoteRequest) (*FetchQuoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FetchQuote not implemented")
+}
+func (UnimplementedPriceFetcherServer) FetchPriceHistory(*FetchPriceHistoryRequest, grpc.ServerStreamingServer[FetchPriceHistoryResponse]) error {
+	return status.Error(codes.Unimplemented, "method FetchPriceHistory not implemented")
+}
+func (UnimplementedPriceFetcherServer) CreateAlert(context.Context, *CreateAlertRequest) (*Alert, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAlert not implemented")
+}
+func (UnimplementedPriceFetcherServer) GetAlert(context.Context, *GetAlertRequest) (*Alert, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAlert not implemented")
+}
+func (UnimplementedPriceFetcherServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedPriceFetcherServer) DeleteAlert(context.Context, *DeleteAlertRequest) (*DeleteAlertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAlert not implemented")
+}
+func (UnimplementedPriceFetcherServer) mustEmbedUnimplementedPriceFetcherServer() {}
+func (UnimplementedPriceFetcherServer) testEmbeddedByValue()                      {}
+
+// UnsafePriceFetcherServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PriceFetcherServer will
+// result in compilation errors.
+type UnsafePriceFetcherServer interface {
+	mustEmbedUnimplementedPriceFetcherServer()
+}
+
+func RegisterPriceFetcherServer(s grpc.ServiceRegistrar, srv PriceFetcherServer) {
+	// If the following call panics, it indicates UnimplementedPriceFetcherServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PriceFetcher_ServiceDesc, srv)
+}
+
+func _PriceFetcher_FetchPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).FetchPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_FetchPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).FetchPrice(ctx, req.(*FetchPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_FetchPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).FetchPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_FetchPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).FetchPrices(ctx, req.(*FetchPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_StreamPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceFetcherServer).StreamPrices(m, &grpc.GenericServerStream[StreamPricesRequest, StreamPricesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_StreamPricesServer = grpc.ServerStreamingServer[StreamPricesResponse]
+
+func _PriceFetcher_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PriceFetcherServer).Subscribe(&grpc.GenericServerStream[SubscribeRequest, SubscribeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_SubscribeServer = grpc.BidiStreamingServer[SubscribeRequest, SubscribeResponse]
+
+func _PriceFetcher_FetchQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).FetchQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_FetchQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).FetchQuote(ctx, req.(*FetchQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_FetchPriceHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchPriceHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceFetcherServer).FetchPriceHistory(m, &grpc.GenericServerStream[FetchPriceHistoryRequest, FetchPriceHistoryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceFetcher_FetchPriceHistoryServer = grpc.ServerStreamingServer[FetchPriceHistoryResponse]
+
+func _PriceFetcher_CreateAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).CreateAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_CreateAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).CreateAlert(ctx, req.(*CreateAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_GetAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).GetAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_GetAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).GetAlert(ctx, req.(*GetAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceFetcher_DeleteAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceFetcherServer).DeleteAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceFetcher_DeleteAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceFetcherServer).DeleteAlert(ctx, req.(*DeleteAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PriceFetcher_ServiceDesc is the grpc.ServiceDesc for PriceFetcher service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PriceFetcher_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pricefetcher.v2.PriceFetcher",
+	HandlerType: (*PriceFetcherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FetchPrice",
+			Handler:    _PriceFetcher_FetchPrice_Handler,
+		},
+		{
+			MethodName: "FetchPrices",
+			Handler:    _PriceFetcher_FetchPrices_Handler,
+		},
+		{
+			MethodName: "FetchQuote",
+			Handler:    _PriceFetcher_FetchQuote_Handler,
+		},
+		{
+			MethodName: "CreateAlert",
+			Handler:    _PriceFetcher_CreateAlert_Handler,
+		},
+		{
+			MethodName: "GetAlert",
+			Handler:    _PriceFetcher_GetAlert_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _PriceFetcher_ListAlerts_Handler,
+		},
+		{
+			MethodName: "DeleteAlert",
+			Handler:    _PriceFetcher_DeleteAlert_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPrices",
+			Handler:       _PriceFetcher_StreamPrices_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _PriceFetcher_Subscribe_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FetchPriceHistory",
+			Handler:       _PriceFetcher_FetchPriceHistory_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/v2/service.proto",
+}